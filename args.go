@@ -0,0 +1,132 @@
+package sqltemplate
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+// An argList collects the arguments for a query built by ExecuteArgs.
+type argList struct {
+	// format is used to format values which cannot be passed as
+	// arguments.
+	format func(interface{}) (RawSQL, error)
+
+	// dialect is the dialect of the query. If it implements
+	// argConverter then it is used to convert values that database
+	// drivers do not accept.
+	dialect Dialect
+
+	// style is the placeholder style used in the query.
+	style PlaceholderStyle
+
+	// reuse is set if arguments with equal values should share a
+	// placeholder. Values are compared with ==, not by identity.
+	reuse bool
+
+	args  []interface{}
+	index map[interface{}]int
+}
+
+// literal is used as the sqlliteral function when executing a template
+// with ExecuteArgs. Values are added to the argument list and a
+// placeholder is returned in their place. Values that implement
+// SQLLiteraler, but not driver.Valuer, cannot be passed to a driver so
+// are formatted instead.
+//
+// Slices, arrays and maps are not accepted by database drivers. If the
+// dialect implements argConverter then they are converted to a form that
+// is, otherwise they are also formatted instead, so that Execute and
+// ExecuteArgs agree on the values they accept. An argConverter may also
+// have values formatted, such as Postgres values that use an encoder from
+// its Registry.
+func (l *argList) literal(v interface{}) (RawSQL, error) {
+	switch v.(type) {
+	case RawSQL, Identifier:
+		return l.format(v)
+	case SQLLiteraler:
+		if _, ok := v.(driver.Valuer); !ok {
+			return l.format(v)
+		}
+	}
+	if c, ok := l.dialect.(argConverter); ok {
+		var err error
+		if v, err = c.arg(v); err != nil {
+			return "", err
+		}
+		if s, ok := v.(RawSQL); ok {
+			return s, nil
+		}
+	} else if isContainer(v) {
+		return l.format(v)
+	}
+	reuse := l.reuse && l.style.Reusable() && v != nil && isComparable(reflect.TypeOf(v))
+	if reuse {
+		if n, ok := l.index[v]; ok {
+			return RawSQL(l.style.Placeholder(n)), nil
+		}
+	}
+	n := len(l.args) + 1
+	l.args = append(l.args, l.style.Arg(n, v))
+	if reuse {
+		if l.index == nil {
+			l.index = make(map[interface{}]int)
+		}
+		l.index[v] = n
+	}
+	return RawSQL(l.style.Placeholder(n)), nil
+}
+
+// An argConverter is implemented by dialects that can convert values to
+// a form that database drivers accept as query arguments.
+type argConverter interface {
+	// arg converts v to a value that can be passed to a database
+	// driver. Values that need no conversion are returned unchanged.
+	// A value that cannot be passed as an argument is returned
+	// formatted as a RawSQL value, which is written inline.
+	arg(v interface{}) (interface{}, error)
+}
+
+// isContainer reports whether v, after following pointers, is a slice,
+// other than a byte slice, an array or a map that does not implement
+// driver.Valuer.
+func isContainer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for {
+		if !rv.IsValid() || rv.Type().Implements(valuerType) {
+			return false
+		}
+		switch rv.Kind() {
+		case reflect.Ptr:
+			if rv.IsNil() {
+				return false
+			}
+			rv = rv.Elem()
+		case reflect.Slice:
+			return rv.Type().Elem().Kind() != reflect.Uint8
+		case reflect.Array, reflect.Map:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// isComparable determines whether all values of type t can be compared
+// without panicking. Unlike reflect.Type.Comparable, types that contain
+// interface values are not considered comparable.
+func isComparable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return false
+	case reflect.Array:
+		return isComparable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isComparable(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return t.Comparable()
+}
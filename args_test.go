@@ -0,0 +1,358 @@
+package sqltemplate
+
+import (
+	"database/sql/driver"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestArgListReuse(t *testing.T) {
+	now := time.Now()
+	l := argList{
+		format: PostgresLiteral,
+		style:  DollarPlaceholders,
+		reuse:  true,
+	}
+	var placeholders []RawSQL
+	for _, v := range []interface{}{"a", 1, "a", &now, []byte("b"), []byte("b"), &now, nil, nil, Identifier("c")} {
+		s, err := l.literal(v)
+		qt.Assert(t, err, qt.IsNil)
+		placeholders = append(placeholders, s)
+	}
+	qt.Check(t, placeholders, qt.DeepEquals, []RawSQL{"$1", "$2", "$1", "$3", "$4", "$5", "$3", "$6", "$7", `"c"`})
+	qt.Check(t, l.args, qt.DeepEquals, []interface{}{"a", 1, &now, []byte("b"), []byte("b"), nil, nil})
+}
+
+func TestArgListReuseNotReusable(t *testing.T) {
+	l := argList{
+		format: PostgresLiteral,
+		style:  QuestionPlaceholders,
+		reuse:  true,
+	}
+	for i := 0; i < 2; i++ {
+		s, err := l.literal("a")
+		qt.Assert(t, err, qt.IsNil)
+		qt.Check(t, s, qt.Equals, RawSQL("?"))
+	}
+	qt.Check(t, l.args, qt.DeepEquals, []interface{}{"a", "a"})
+}
+
+func TestArgListSQLLiteraler(t *testing.T) {
+	l := argList{
+		format: PostgresLiteral,
+		style:  DollarPlaceholders,
+	}
+	s, err := l.literal(&ptrLiteraler{})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, s, qt.Equals, RawSQL("non-nil receiver"))
+
+	s, err = l.literal(testEnum("active"))
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, s, qt.Equals, RawSQL("$1"))
+	qt.Check(t, l.args, qt.DeepEquals, []interface{}{testEnum("active")})
+}
+
+func TestIsComparable(t *testing.T) {
+	qt.Check(t, isComparable(reflect.TypeOf("")), qt.IsTrue)
+	qt.Check(t, isComparable(reflect.TypeOf(time.Time{})), qt.IsTrue)
+	qt.Check(t, isComparable(reflect.TypeOf([2]int{})), qt.IsTrue)
+	qt.Check(t, isComparable(reflect.TypeOf([]byte{})), qt.IsFalse)
+	qt.Check(t, isComparable(reflect.TypeOf([2]interface{}{})), qt.IsFalse)
+	qt.Check(t, isComparable(reflect.TypeOf(struct{ V interface{} }{})), qt.IsFalse)
+}
+
+var argListConvertTests = []struct {
+	name        string
+	dialect     Dialect
+	value       interface{}
+	expectSQL   RawSQL
+	expectArgs  []interface{}
+	expectError string
+}{{
+	name:       "slice",
+	dialect:    Postgres{},
+	value:      []int{1, 2},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{Array{Elems: []int{1, 2}}},
+}, {
+	name:       "slice pointer",
+	dialect:    Postgres{},
+	value:      &[]string{"a"},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{Array{Elems: []string{"a"}}},
+}, {
+	name:       "array",
+	dialect:    Postgres{},
+	value:      [2]int{1, 2},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{Array{Elems: [2]int{1, 2}}},
+}, {
+	name:       "typed array",
+	dialect:    Postgres{},
+	value:      Array{Elems: []int{1}, ElemType: "integer"},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{Array{Elems: []int{1}, ElemType: "integer"}},
+}, {
+	name:       "bytes",
+	dialect:    Postgres{},
+	value:      []byte("a"),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{[]byte("a")},
+}, {
+	name:       "uuid",
+	dialect:    Postgres{},
+	value:      testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"123e4567-e89b-12d3-a456-426614174000"},
+}, {
+	name:       "hstore",
+	dialect:    Postgres{},
+	value:      map[string]string{"b": "2", "a": "1"},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{`"a"=>"1", "b"=>"2"`},
+}, {
+	name:       "nil hstore",
+	dialect:    Postgres{},
+	value:      map[string]string(nil),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{nil},
+}, {
+	name:       "duration",
+	dialect:    Postgres{},
+	value:      90*time.Minute + time.Microsecond,
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"1 hour 30 minutes 0.000001 seconds"},
+}, {
+	name:       "duration pointer",
+	dialect:    Postgres{},
+	value:      func() *time.Duration { d := -time.Second; return &d }(),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"-1 seconds"},
+}, {
+	name:       "duration without intervals",
+	dialect:    MySQL{},
+	value:      time.Second,
+	expectSQL:  "$1",
+	expectArgs: []interface{}{time.Second},
+}, {
+	name:       "ip",
+	dialect:    Postgres{},
+	value:      net.ParseIP("192.168.0.1"),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"192.168.0.1"},
+}, {
+	name:       "ip network",
+	dialect:    Postgres{},
+	value:      mustParseCIDR("10.1.0.0/16"),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"10.1.0.0/16"},
+}, {
+	name:        "invalid ip network",
+	dialect:     Postgres{},
+	value:       net.IPNet{IP: net.ParseIP("::1"), Mask: net.CIDRMask(8, 32)},
+	expectError: `invalid IP network ::1 with mask ff000000`,
+}, {
+	name:       "netip addr",
+	dialect:    Postgres{},
+	value:      netip.MustParseAddr("::1"),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"::1"},
+}, {
+	name:       "netip prefix",
+	dialect:    Postgres{},
+	value:      netip.MustParsePrefix("10.0.0.1/8"),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"10.0.0.1/8"},
+}, {
+	name:       "hardware address",
+	dialect:    Postgres{},
+	value:      mustParseMAC("08:00:2b:01:02:03"),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"08:00:2b:01:02:03"},
+}, {
+	name:       "nil ip",
+	dialect:    Postgres{},
+	value:      net.IP(nil),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{nil},
+}, {
+	name:       "tagged struct",
+	dialect:    Postgres{},
+	value:      testArgRow{ID: 1, Name: "x"},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{Row{Struct: testArgRow{ID: 1, Name: "x"}}},
+}, {
+	name:       "tagged struct pointer",
+	dialect:    Postgres{},
+	value:      &testArgRow{ID: 1, Name: "x"},
+	expectSQL:  "$1",
+	expectArgs: []interface{}{Row{Struct: &testArgRow{ID: 1, Name: "x"}}},
+}, {
+	name:       "big int",
+	dialect:    Postgres{},
+	value:      new(big.Int).Lsh(big.NewInt(1), 100),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"1267650600228229401496703205376"},
+}, {
+	name:       "big rat",
+	dialect:    Postgres{},
+	value:      big.NewRat(-1, 8),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"-0.125"},
+}, {
+	name:      "big rat without decimal form",
+	dialect:   Postgres{},
+	value:     big.NewRat(1, 3),
+	expectSQL: "(1::numeric / 3)",
+}, {
+	name:       "big float",
+	dialect:    Postgres{},
+	value:      *big.NewFloat(1.5),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"1.5"},
+}, {
+	name:        "infinite big float",
+	dialect:     Postgres{},
+	value:       big.NewFloat(math.Inf(1)),
+	expectError: `cannot represent \+Inf as numeric`,
+}, {
+	name:       "large uint64",
+	dialect:    Postgres{},
+	value:      uint64(math.MaxUint64),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{"18446744073709551615"},
+}, {
+	name:       "small uint64",
+	dialect:    Postgres{},
+	value:      uint64(1),
+	expectSQL:  "$1",
+	expectArgs: []interface{}{uint64(1)},
+}, {
+	name:        "other map",
+	dialect:     Postgres{},
+	value:       map[string]int{"a": 1},
+	expectError: `unknown type map\[string\]int`,
+}, {
+	name:        "slice without arrays",
+	dialect:     MySQL{},
+	value:       []int{1},
+	expectError: `unknown type \[\]int`,
+}}
+
+type testArgRow struct {
+	ID   int    `sql:"id"`
+	Name string `sql:"name"`
+}
+
+func TestArgListConvert(t *testing.T) {
+	for _, test := range argListConvertTests {
+		t.Run(test.name, func(t *testing.T) {
+			l := argList{
+				format:  test.dialect.Literal,
+				dialect: test.dialect,
+				style:   DollarPlaceholders,
+			}
+			s, err := l.literal(test.value)
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, test.expectSQL)
+			qt.Check(t, l.args, qt.DeepEquals, test.expectArgs)
+		})
+	}
+}
+
+var executeArgsValueTests = []struct {
+	name        string
+	value       interface{}
+	expectValue driver.Value
+	expectError string
+}{{
+	name:        "ip addresses",
+	value:       []net.IP{net.ParseIP("1.2.3.4")},
+	expectValue: `{"1.2.3.4"}`,
+}, {
+	name:        "durations",
+	value:       []time.Duration{5 * time.Second},
+	expectValue: `{"5 seconds"}`,
+}, {
+	name:        "large uint64s",
+	value:       []uint64{math.MaxUint64},
+	expectValue: `{"18446744073709551615"}`,
+}, {
+	name:        "big ints",
+	value:       []*big.Int{big.NewInt(-7)},
+	expectValue: `{"-7"}`,
+}, {
+	name:        "big rat without decimal form",
+	value:       []*big.Rat{big.NewRat(1, 3)},
+	expectError: `cannot represent \(1::numeric / 3\) as text`,
+}, {
+	name:        "nested row",
+	value:       testArgNested{Row: testArgRow{ID: 1, Name: "x"}, IDs: []int{1, 2}},
+	expectValue: `("(\"1\",\"x\")","{\"1\",\"2\"}")`,
+}, {
+	name:        "big int range",
+	value:       Range[big.Int]{Lower: *big.NewInt(1), Upper: *big.NewInt(10)},
+	expectValue: `("1","10")`,
+}, {
+	name:        "cycle",
+	value:       func() *testCycle { c := new(testCycle); c.Next = c; return c }(),
+	expectError: `.*the value might contain a cycle`,
+}}
+
+func TestExecuteArgsValues(t *testing.T) {
+	tmpl, err := New("").Parse(`SELECT {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	for _, test := range executeArgsValueTests {
+		t.Run(test.name, func(t *testing.T) {
+			_, args, err := tmpl.ExecuteArgs(test.value)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Assert(t, args, qt.HasLen, 1)
+			v, err := driver.DefaultParameterConverter.ConvertValue(args[0])
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, v, qt.Equals, test.expectValue)
+		})
+	}
+}
+
+type testArgNested struct {
+	Row testArgRow `sql:"row"`
+	IDs []int      `sql:"ids"`
+}
+
+func TestExecuteArgsArrays(t *testing.T) {
+	tmpl, err := New("").Parse(`SELECT * FROM t WHERE a = ANY({{.A}}) AND b = {{.B}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	query, args, err := tmpl.ExecuteArgs(map[string]interface{}{
+		"A": []int{1, 2},
+		"B": Array{Elems: []string{"x"}, ElemType: "text"},
+	})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `SELECT * FROM t WHERE a = ANY($1) AND b = $2`)
+	qt.Assert(t, args, qt.HasLen, 2)
+	for i, expect := range []driver.Value{`{"1","2"}`, `{"x"}`} {
+		v, err := args[i].(driver.Valuer).Value()
+		qt.Assert(t, err, qt.IsNil)
+		qt.Check(t, v, qt.Equals, expect)
+	}
+
+	tmpl, err = New("").WithDialect(MySQL{}).Parse(`SELECT {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	_, _, err = tmpl.ExecuteArgs([]int{1})
+	qt.Check(t, err, qt.ErrorMatches, `.*unknown type \[\]int`)
+}
@@ -0,0 +1,161 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BigQuery is the Dialect for Google BigQuery's GoogleSQL.
+type BigQuery struct{}
+
+// Name implements Dialect.
+func (BigQuery) Name() string {
+	return "bigquery"
+}
+
+// QuoteIdentifier implements Dialect. Identifiers are quoted using
+// backticks, see
+// https://cloud.google.com/bigquery/docs/reference/standard-sql/lexical#quoted_identifiers.
+func (BigQuery) QuoteIdentifier(name string) (RawSQL, error) {
+	return RawSQL("`" + bigqueryEscape(name, '`') + "`"), nil
+}
+
+// Bool implements Dialect.
+func (BigQuery) Bool(b bool) (RawSQL, error) {
+	if b {
+		return RawSQL("TRUE"), nil
+	}
+	return RawSQL("FALSE"), nil
+}
+
+// Null implements Dialect.
+func (BigQuery) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, BigQuery drivers use
+// QuestionPlaceholders.
+func (BigQuery) Placeholders() PlaceholderStyle {
+	return QuestionPlaceholders
+}
+
+// Supports implements Dialect. BigQuery supports FeatureBooleans,
+// FeatureArrays, FeatureBackslashEscapes, FeatureHashComments,
+// FeatureTripleQuoting, FeatureBackslashQuotes and
+// FeatureBackslashIdentifiers.
+func (BigQuery) Supports(f Feature) bool {
+	switch f {
+	case FeatureBooleans, FeatureArrays, FeatureBackslashEscapes, FeatureHashComments, FeatureTripleQuoting, FeatureBackslashQuotes, FeatureBackslashIdentifiers:
+		return true
+	}
+	return false
+}
+
+// BigQueryLiteral formats the value v as a literal suitable for use in
+// queries used with Google BigQuery. It is equivalent to calling the
+// Literal method of a BigQuery value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  Either the SQL keyword TRUE, or FALSE.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  If the value represents +Inf, -Inf or NaN then the literal will be
+//	  CAST('inf' AS FLOAT64), CAST('-inf' AS FLOAT64) or
+//	  CAST('nan' AS FLOAT64) respectively. Otherwise the %g encoding
+//	  provided by fmt.Printf is used.
+//	string
+//	  A string literal, with backslashes, quotes and control characters
+//	  escaped using backslashes.
+//	[]byte
+//	  A bytes literal with every byte written as a \xHH escape sequence,
+//	  for example B'\x74\x65\x73\x74'.
+//	time.Time
+//	  A TIMESTAMP literal with microsecond precision.
+//	Identifier
+//	  A quoted identifier, see
+//	  https://cloud.google.com/bigquery/docs/reference/standard-sql/lexical#quoted_identifiers.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func BigQueryLiteral(v interface{}) (RawSQL, error) {
+	return BigQuery{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// BigQueryLiteral.
+func (d BigQuery) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if v1 == nil {
+			return d.Null(), nil
+		}
+		return RawSQL(`B'` + escapeBytes(v1) + `'`), nil
+	case float64:
+		switch {
+		case math.IsInf(v1, 1):
+			return RawSQL("CAST('inf' AS FLOAT64)"), nil
+		case math.IsInf(v1, -1):
+			return RawSQL("CAST('-inf' AS FLOAT64)"), nil
+		case math.IsNaN(v1):
+			return RawSQL("CAST('nan' AS FLOAT64)"), nil
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		return RawSQL(`'` + bigqueryEscape(v1, '\'') + `'`), nil
+	case time.Time:
+		return RawSQL(`TIMESTAMP '` + v1.Format("2006-01-02 15:04:05.999999-07:00") + `'`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
+
+// bigqueryEscape escapes s for use between the quote characters q, see
+// https://cloud.google.com/bigquery/docs/reference/standard-sql/lexical#escape_sequences.
+func bigqueryEscape(s string, q rune) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == q || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
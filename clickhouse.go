@@ -0,0 +1,140 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClickHouse is the Dialect for the ClickHouse database.
+type ClickHouse struct{}
+
+// Name implements Dialect.
+func (ClickHouse) Name() string {
+	return "clickhouse"
+}
+
+// QuoteIdentifier implements Dialect. Identifiers are quoted using
+// backticks, see https://clickhouse.com/docs/en/sql-reference/syntax#identifiers.
+func (ClickHouse) QuoteIdentifier(name string) (RawSQL, error) {
+	return RawSQL("`" + clickhouseIdentifierEscaper.Replace(name) + "`"), nil
+}
+
+// Bool implements Dialect.
+func (ClickHouse) Bool(b bool) (RawSQL, error) {
+	if b {
+		return RawSQL("true"), nil
+	}
+	return RawSQL("false"), nil
+}
+
+// Null implements Dialect.
+func (ClickHouse) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, ClickHouse drivers use
+// QuestionPlaceholders.
+func (ClickHouse) Placeholders() PlaceholderStyle {
+	return QuestionPlaceholders
+}
+
+// Supports implements Dialect. ClickHouse supports FeatureBooleans,
+// FeatureArrays, FeatureBackslashEscapes, FeatureHashComments and
+// FeatureBackslashIdentifiers.
+func (ClickHouse) Supports(f Feature) bool {
+	switch f {
+	case FeatureBooleans, FeatureArrays, FeatureBackslashEscapes, FeatureHashComments, FeatureBackslashIdentifiers:
+		return true
+	}
+	return false
+}
+
+var (
+	clickhouseStringEscaper     = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	clickhouseIdentifierEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+)
+
+// ClickHouseLiteral formats the value v as a literal suitable for use in
+// queries used with the ClickHouse database. It is equivalent to calling
+// the Literal method of a ClickHouse value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  Either true, or false.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  If the value represents +Inf, -Inf or NaN then the literal will be
+//	  inf, -inf or nan respectively. Otherwise the %g encoding provided
+//	  by fmt.Printf is used.
+//	string
+//	  A string literal, with backslashes and single quotes escaped
+//	  using backslashes.
+//	[]byte
+//	  A string literal with every byte written as a \xHH escape
+//	  sequence.
+//	time.Time
+//	  A call to toDateTime64 with the time in UTC and nanosecond
+//	  precision.
+//	Identifier
+//	  A quoted identifier, see
+//	  https://clickhouse.com/docs/en/sql-reference/syntax#identifiers.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func ClickHouseLiteral(v interface{}) (RawSQL, error) {
+	return ClickHouse{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// ClickHouseLiteral.
+func (d ClickHouse) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if v1 == nil {
+			return d.Null(), nil
+		}
+		return RawSQL(`'` + escapeBytes(v1) + `'`), nil
+	case float64:
+		switch {
+		case math.IsInf(v1, 1):
+			return RawSQL("inf"), nil
+		case math.IsInf(v1, -1):
+			return RawSQL("-inf"), nil
+		case math.IsNaN(v1):
+			return RawSQL("nan"), nil
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		return RawSQL(`'` + clickhouseStringEscaper.Replace(v1) + `'`), nil
+	case time.Time:
+		return RawSQL(`toDateTime64('` + v1.UTC().Format("2006-01-02 15:04:05.999999999") + `', 9, 'UTC')`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
@@ -0,0 +1,202 @@
+package sqltemplate
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+var clickhouseLiteralTests = []struct {
+	name      string
+	value     interface{}
+	expectSQL RawSQL
+}{{
+	name:      "nil",
+	value:     nil,
+	expectSQL: `NULL`,
+}, {
+	name:      "simple string",
+	value:     "test string",
+	expectSQL: `'test string'`,
+}, {
+	name:      "simple string pointer",
+	value:     newString("test string"),
+	expectSQL: `'test string'`,
+}, {
+	name:      "string with quotes",
+	value:     "test 'string' \\ \"quoted\"",
+	expectSQL: `'test \'string\' \\ "quoted"'`,
+}, {
+	name:      "nil string pointer",
+	value:     (*string)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "raw sql",
+	value:     RawSQL("'; DROP TABLE users;"),
+	expectSQL: `'; DROP TABLE users;`,
+}, {
+	name:      "identifier",
+	value:     Identifier("test identifier"),
+	expectSQL: "`test identifier`",
+}, {
+	name:      "identifier with quotes",
+	value:     Identifier("test `identifier` \"quoted\""),
+	expectSQL: "`test \\`identifier\\` \"quoted\"`",
+}, {
+	name:      "true",
+	value:     true,
+	expectSQL: `true`,
+}, {
+	name:      "true pointer",
+	value:     newBool(true),
+	expectSQL: `true`,
+}, {
+	name:      "false",
+	value:     false,
+	expectSQL: `false`,
+}, {
+	name:      "false pointer",
+	value:     newBool(false),
+	expectSQL: `false`,
+}, {
+	name:      "nil bool pointer",
+	value:     (*bool)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "bytes",
+	value:     []byte("test"),
+	expectSQL: `'\x74\x65\x73\x74'`,
+}, {
+	name:      "empty bytes",
+	value:     []byte{},
+	expectSQL: `''`,
+}, {
+	name:      "nil bytes",
+	value:     []byte(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "float",
+	value:     3.141592654,
+	expectSQL: `3.141592654`,
+}, {
+	name:      "float pointer",
+	value:     newFloat(3.141592654),
+	expectSQL: `3.141592654`,
+}, {
+	name:      "float Inf",
+	value:     math.Inf(0),
+	expectSQL: `inf`,
+}, {
+	name:      "float Inf pointer",
+	value:     newFloat(math.Inf(0)),
+	expectSQL: `inf`,
+}, {
+	name:      "float -Inf",
+	value:     math.Inf(-1),
+	expectSQL: `-inf`,
+}, {
+	name:      "float NaN",
+	value:     math.NaN(),
+	expectSQL: `nan`,
+}, {
+	name:      "nil float pointer",
+	value:     (*float64)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "int",
+	value:     0,
+	expectSQL: `0`,
+}, {
+	name:      "int pointer",
+	value:     newInt(0),
+	expectSQL: `0`,
+}, {
+	name:      "nil int pointer",
+	value:     (*int)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "int64",
+	value:     int64(1e9),
+	expectSQL: `1000000000`,
+}, {
+	name:      "int64 pointer",
+	value:     newInt64(1e9),
+	expectSQL: `1000000000`,
+}, {
+	name:      "nil int64 pointer",
+	value:     (*int64)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "time",
+	value:     time.Date(2020, time.February, 2, 12, 30, 45, 300001000, time.UTC),
+	expectSQL: `toDateTime64('2020-02-02 12:30:45.300001', 9, 'UTC')`,
+}, {
+	name:      "time pointer",
+	value:     newTime(time.Date(2020, time.February, 2, 12, 30, 45, 300001000, time.UTC)),
+	expectSQL: `toDateTime64('2020-02-02 12:30:45.300001', 9, 'UTC')`,
+}, {
+	name:      "niltime pointer",
+	value:     (*time.Time)(nil),
+	expectSQL: `NULL`,
+}, {
+	name: "valuer",
+	value: sql.NullTime{
+		Valid: true,
+		Time:  time.Date(2020, time.February, 2, 12, 30, 45, 300005000, time.FixedZone("UTC-3", -3*60*60)),
+	},
+	expectSQL: `toDateTime64('2020-02-02 15:30:45.300005', 9, 'UTC')`,
+}}
+
+func TestClickHouseLiteral(t *testing.T) {
+	for _, test := range clickhouseLiteralTests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := ClickHouseLiteral(test.value)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, test.expectSQL)
+		})
+	}
+}
+
+func TestClickHouseLiteralInTemplate(t *testing.T) {
+	tmpl, err := New("").WithDialect(ClickHouse{}).Parse(`{{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	for _, test := range clickhouseLiteralTests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := new(strings.Builder)
+			err := tmpl.Execute(sb, test.value)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, sb.String(), qt.Equals, string(test.expectSQL))
+		})
+	}
+}
+
+func TestClickHouseLiteralUnknown(t *testing.T) {
+	_, err := ClickHouseLiteral(make(chan bool))
+	qt.Check(t, err, qt.ErrorMatches, `unknown type chan bool`)
+}
+
+func TestClickHouseDialect(t *testing.T) {
+	var d Dialect = ClickHouse{}
+	qt.Check(t, d.Name(), qt.Equals, "clickhouse")
+	qt.Check(t, d.Null(), qt.Equals, RawSQL("NULL"))
+	qt.Check(t, d.Placeholders(), qt.Equals, QuestionPlaceholders)
+	qt.Check(t, d.Supports(FeatureBooleans), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureArrays), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureDollarQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashEscapes), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureEscapeStrings), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureHashComments), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureNestedComments), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureDashCommentSpace), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureQQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureTripleQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashQuotes), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashIdentifiers), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureBacktickIdentifiers), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBracketIdentifiers), qt.IsFalse)
+}
@@ -0,0 +1,348 @@
+package sqltemplate
+
+import "bytes"
+
+// A state is the lexical state of the SQL text at a point in a template.
+type state uint8
+
+const (
+	stateCode state = iota
+	stateString
+	stateQuotedString
+	stateIdentifier
+	stateDollarQuote
+	stateLineComment
+	stateBlockComment
+)
+
+var stateNames = [...]string{
+	stateCode:         "SQL code",
+	stateString:       "a string literal",
+	stateQuotedString: "a string literal with custom delimiters",
+	stateIdentifier:   "a quoted identifier",
+	stateDollarQuote:  "a dollar-quoted string",
+	stateLineComment:  "a line comment",
+	stateBlockComment: "a block comment",
+}
+
+func (s state) String() string {
+	return stateNames[s]
+}
+
+// A context describes the lexical state of the SQL text at a point in a
+// template. Contexts are comparable, two contexts are equal only if text
+// following them would be lexed identically.
+type context struct {
+	state state
+
+	// delim is the closing delimiter of a string literal or quoted
+	// identifier.
+	delim byte
+
+	// backslash is set if backslashes are escape characters in a string
+	// literal or quoted identifier.
+	backslash bool
+
+	// noEscape is set if the closing delimiter of a quoted identifier
+	// cannot be escaped.
+	noEscape bool
+
+	// pendingEscape is set if the text ended with a backslash that
+	// escapes the character following it in a string literal.
+	pendingEscape bool
+
+	// pendingDash is set if the text ended with -- in SQL code, in a
+	// dialect where that only starts a comment if it is followed by
+	// whitespace.
+	pendingDash bool
+
+	// pendingMinus is set if the text ended with a single - in SQL
+	// code, in a dialect where -- always starts a comment.
+	pendingMinus bool
+
+	// tag is the complete tag, including dollar signs, of a
+	// dollar-quoted string, or the closing delimiter of a string with
+	// custom delimiters. An empty tag in a string with custom
+	// delimiters means that the opening delimiter has not been seen.
+	tag string
+
+	// depth is the nesting depth of a block comment.
+	depth int
+}
+
+// String describes c for use in error messages.
+func (c context) String() string {
+	switch {
+	case c.pendingEscape:
+		return c.state.String() + " after a backslash"
+	case c.pendingDash:
+		return c.state.String() + " after --"
+	case c.pendingMinus:
+		return c.state.String() + " after -"
+	}
+	return c.state.String()
+}
+
+// A syntax holds the lexical rules of an SQL dialect.
+type syntax struct {
+	// identOpen and identClose are the delimiters of quoted
+	// identifiers.
+	identOpen, identClose byte
+
+	// backslashes is set if backslashes are escape characters in
+	// string literals.
+	backslashes bool
+
+	// escapeStrings is set if string literals prefixed with E treat
+	// backslashes as escape characters.
+	escapeStrings bool
+
+	// dollarQuotes is set if strings may be dollar-quoted.
+	dollarQuotes bool
+
+	// qQuotes is set if strings may use alternative quoting, such as
+	// q'[text]'.
+	qQuotes bool
+
+	// tripleQuotes is set if strings may be triple quoted.
+	tripleQuotes bool
+
+	// hashComments is set if # starts a line comment.
+	hashComments bool
+
+	// nestedComments is set if block comments nest.
+	nestedComments bool
+
+	// dashCommentSpace is set if -- only starts a line comment when it
+	// is followed by whitespace or a control character.
+	dashCommentSpace bool
+
+	// backslashQuotes is set if quotes in string literals in which
+	// backslashes are escape characters cannot be escaped by doubling
+	// them.
+	backslashQuotes bool
+
+	// identBackslashes is set if backslashes are escape characters in
+	// quoted identifiers.
+	identBackslashes bool
+
+	// backtickIdents and bracketIdents are set if identifiers may
+	// also be quoted with backticks or square brackets.
+	backtickIdents, bracketIdents bool
+}
+
+// syntaxOf determines the lexical rules of the dialect d from the
+// features it supports. The identifier delimiters are taken from the
+// result of quoting an empty identifier. A double quote that does not
+// delimit identifiers delimits strings.
+func syntaxOf(d Dialect) syntax {
+	s := syntax{
+		identOpen:        '"',
+		identClose:       '"',
+		backslashes:      d.Supports(FeatureBackslashEscapes),
+		escapeStrings:    d.Supports(FeatureEscapeStrings),
+		dollarQuotes:     d.Supports(FeatureDollarQuoting),
+		qQuotes:          d.Supports(FeatureQQuoting),
+		tripleQuotes:     d.Supports(FeatureTripleQuoting),
+		hashComments:     d.Supports(FeatureHashComments),
+		nestedComments:   d.Supports(FeatureNestedComments),
+		dashCommentSpace: d.Supports(FeatureDashCommentSpace),
+		backslashQuotes:  d.Supports(FeatureBackslashQuotes),
+		identBackslashes: d.Supports(FeatureBackslashIdentifiers),
+		backtickIdents:   d.Supports(FeatureBacktickIdentifiers),
+		bracketIdents:    d.Supports(FeatureBracketIdentifiers),
+	}
+	if q, err := d.QuoteIdentifier(""); err == nil && len(q) >= 2 {
+		s.identOpen, s.identClose = q[0], q[len(q)-1]
+	}
+	return s
+}
+
+// lex returns the context at the end of text, given that text starts in
+// context c.
+func (s syntax) lex(c context, text []byte) context {
+	for i := 0; i < len(text); {
+		c, i = s.next(c, text, i)
+	}
+	return c
+}
+
+// next lexes text starting at position i in context c, returning the new
+// context and the position at which lexing should continue.
+func (s syntax) next(c context, text []byte, i int) (context, int) {
+	switch c.state {
+	case stateCode:
+		if c.pendingDash {
+			if isDashCommentSpace(text[i]) {
+				return context{state: stateLineComment}, i
+			}
+		}
+		if c.pendingMinus && text[i] == '-' {
+			return context{state: stateLineComment}, i + 1
+		}
+		return s.nextCode(text, i)
+	case stateString, stateIdentifier:
+		if c.pendingEscape {
+			c.pendingEscape = false
+			return c, i + 1
+		}
+		switch text[i] {
+		case '\\':
+			if c.backslash {
+				if i+1 == len(text) {
+					c.pendingEscape = true
+					return c, i + 1
+				}
+				return c, i + 2
+			}
+		case c.delim:
+			if !c.noEscape && i+1 < len(text) && text[i+1] == c.delim {
+				return c, i + 2
+			}
+			return context{}, i + 1
+		}
+		return c, i + 1
+	case stateQuotedString:
+		switch {
+		case c.tag == "":
+			c.tag = string(closingDelim(text[i])) + "'"
+			return c, i + 1
+		case c.pendingEscape:
+			c.pendingEscape = false
+			return c, i + 1
+		case c.backslash && text[i] == '\\':
+			if i+1 == len(text) {
+				c.pendingEscape = true
+				return c, i + 1
+			}
+			return c, i + 2
+		case bytes.HasPrefix(text[i:], []byte(c.tag)):
+			return context{}, i + len(c.tag)
+		}
+		return c, i + 1
+	case stateDollarQuote:
+		if j := bytes.Index(text[i:], []byte(c.tag)); j >= 0 {
+			return context{}, i + j + len(c.tag)
+		}
+		return c, len(text)
+	case stateLineComment:
+		if j := bytes.IndexByte(text[i:], '\n'); j >= 0 {
+			return context{}, i + j + 1
+		}
+		return c, len(text)
+	case stateBlockComment:
+		switch {
+		case bytes.HasPrefix(text[i:], []byte("*/")):
+			if c.depth == 1 {
+				return context{}, i + 2
+			}
+			c.depth--
+			return c, i + 2
+		case s.nestedComments && bytes.HasPrefix(text[i:], []byte("/*")):
+			c.depth++
+			return c, i + 2
+		}
+		return c, i + 1
+	}
+	panic("unknown state")
+}
+
+// nextCode lexes text starting at position i, which is in SQL code.
+func (s syntax) nextCode(text []byte, i int) (context, int) {
+	switch ch := text[i]; {
+	case s.tripleQuotes && (bytes.HasPrefix(text[i:], []byte("'''")) || bytes.HasPrefix(text[i:], []byte(`"""`))):
+		return context{state: stateQuotedString, tag: string(text[i : i+3]), backslash: s.backslashes}, i + 3
+	case s.qQuotes && (ch == 'q' || ch == 'Q') && i+1 < len(text) && text[i+1] == '\'' && isPrefixStart(text, i, "nN"):
+		// An alternative quoting string, the next character is the
+		// opening delimiter.
+		return context{state: stateQuotedString}, i + 2
+	case ch == '\'':
+		// A string prefixed with E is an escape string constant.
+		escape := s.escapeStrings && i > 0 && (text[i-1] == 'E' || text[i-1] == 'e') && isPrefixStart(text, i-1, "")
+		return context{state: stateString, delim: '\'', backslash: s.backslashes || escape}, i + 1
+	case ch == s.identOpen:
+		return context{state: stateIdentifier, delim: s.identClose, backslash: s.identBackslashes}, i + 1
+	case ch == '`' && s.backtickIdents:
+		return context{state: stateIdentifier, delim: '`'}, i + 1
+	case ch == '[' && s.bracketIdents:
+		return context{state: stateIdentifier, delim: ']', noEscape: true}, i + 1
+	case ch == '"':
+		return context{state: stateString, delim: '"', backslash: s.backslashes}, i + 1
+	case ch == '-' && bytes.HasPrefix(text[i:], []byte("--")):
+		if s.dashCommentSpace {
+			if i+2 == len(text) {
+				return context{pendingDash: true}, i + 2
+			}
+			if !isDashCommentSpace(text[i+2]) {
+				return context{}, i + 2
+			}
+		}
+		return context{state: stateLineComment}, i + 2
+	case ch == '-' && i+1 == len(text) && !s.dashCommentSpace:
+		return context{pendingMinus: true}, i + 1
+	case ch == '#' && s.hashComments:
+		return context{state: stateLineComment}, i + 1
+	case ch == '/' && bytes.HasPrefix(text[i:], []byte("/*")):
+		return context{state: stateBlockComment, depth: 1}, i + 2
+	case ch == '$' && s.dollarQuotes && (i == 0 || !isWordChar(text[i-1])):
+		if tag := dollarTag(text[i:]); tag != "" {
+			return context{state: stateDollarQuote, tag: tag}, i + len(tag)
+		}
+	}
+	return context{}, i + 1
+}
+
+// dollarTag returns the dollar-quote tag at the start of text, or an empty
+// string if text does not start with a tag.
+func dollarTag(text []byte) string {
+	j := 1
+	for j < len(text) && isWordChar(text[j]) {
+		j++
+	}
+	if j == len(text) || text[j] != '$' {
+		return ""
+	}
+	if j > 1 && text[1] >= '0' && text[1] <= '9' {
+		// A tag cannot start with a digit, this is a positional
+		// parameter.
+		return ""
+	}
+	return string(text[:j+1])
+}
+
+// isPrefixStart reports whether the string prefix starting at position
+// i of text is not part of a longer word. The prefix may itself be
+// preceded by one of the characters in extra.
+func isPrefixStart(text []byte, i int, extra string) bool {
+	if i > 0 && bytes.IndexByte([]byte(extra), text[i-1]) >= 0 {
+		i--
+	}
+	return i == 0 || !isWordChar(text[i-1])
+}
+
+// closingDelim returns the closing delimiter matching the opening
+// delimiter c of an alternative quoting string.
+func closingDelim(c byte) byte {
+	switch c {
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	case '<':
+		return '>'
+	case '(':
+		return ')'
+	}
+	return c
+}
+
+// isDashCommentSpace reports whether c, following --, makes it start a
+// comment in dialects that support FeatureDashCommentSpace.
+func isDashCommentSpace(c byte) bool {
+	return c <= ' ' || c == 0x7f
+}
+
+// isWordChar reports whether c can be part of an unquoted identifier.
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,218 @@
+package sqltemplate
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+var lexTests = []struct {
+	name          string
+	dialect       Dialect
+	text          string
+	expectContext context
+}{{
+	name:          "code",
+	dialect:       Postgres{},
+	text:          "SELECT * FROM t WHERE a = ",
+	expectContext: context{},
+}, {
+	name:          "string",
+	dialect:       Postgres{},
+	text:          "SELECT 'a''b",
+	expectContext: context{state: stateString, delim: '\''},
+}, {
+	name:          "closed string",
+	dialect:       Postgres{},
+	text:          "SELECT 'a''b' || ",
+	expectContext: context{},
+}, {
+	name:          "escape string",
+	dialect:       Postgres{},
+	text:          `SELECT E'a\'`,
+	expectContext: context{state: stateString, delim: '\'', backslash: true},
+}, {
+	name:          "escape string ending with backslash",
+	dialect:       Postgres{},
+	text:          `SELECT E'a\`,
+	expectContext: context{state: stateString, delim: '\'', backslash: true, pendingEscape: true},
+}, {
+	name:          "escaped backslash",
+	dialect:       Postgres{},
+	text:          `SELECT E'a\\`,
+	expectContext: context{state: stateString, delim: '\'', backslash: true},
+}, {
+	name:          "not escape string",
+	dialect:       Postgres{},
+	text:          `SELECT nameE'a\'`,
+	expectContext: context{},
+}, {
+	name:          "backslash string",
+	dialect:       MySQL{},
+	text:          `SELECT 'a\'`,
+	expectContext: context{state: stateString, delim: '\'', backslash: true},
+}, {
+	name:          "backslash string ending with backslash",
+	dialect:       MySQL{},
+	text:          `SELECT 'a\`,
+	expectContext: context{state: stateString, delim: '\'', backslash: true, pendingEscape: true},
+}, {
+	name:          "no backslash escapes",
+	dialect:       MySQL{NoBackslashEscapes: true},
+	text:          `SELECT 'a\'`,
+	expectContext: context{},
+}, {
+	name:          "double quoted string",
+	dialect:       MySQL{},
+	text:          `SELECT "a`,
+	expectContext: context{state: stateString, delim: '"', backslash: true},
+}, {
+	name:          "identifier",
+	dialect:       Postgres{},
+	text:          `SELECT "a""b`,
+	expectContext: context{state: stateIdentifier, delim: '"'},
+}, {
+	name:          "closed identifier",
+	dialect:       Postgres{},
+	text:          `SELECT "a""b" FROM `,
+	expectContext: context{},
+}, {
+	name:          "backtick identifier",
+	dialect:       MySQL{},
+	text:          "SELECT `a",
+	expectContext: context{state: stateIdentifier, delim: '`'},
+}, {
+	name:          "ansi quotes identifier",
+	dialect:       MySQL{ANSIQuotes: true},
+	text:          `SELECT "a`,
+	expectContext: context{state: stateIdentifier, delim: '"'},
+}, {
+	name:          "bracket identifier",
+	dialect:       SQLServer{},
+	text:          "SELECT [a]]b",
+	expectContext: context{state: stateIdentifier, delim: ']'},
+}, {
+	name:          "postgres array subscript",
+	dialect:       Postgres{},
+	text:          "SELECT a[",
+	expectContext: context{},
+}, {
+	name:          "line comment",
+	dialect:       Postgres{},
+	text:          "SELECT 1 -- comment 'a",
+	expectContext: context{state: stateLineComment},
+}, {
+	name:          "closed line comment",
+	dialect:       Postgres{},
+	text:          "SELECT 1 -- comment 'a\nFROM ",
+	expectContext: context{},
+}, {
+	name:          "hash comment",
+	dialect:       MySQL{},
+	text:          "SELECT 1 # comment",
+	expectContext: context{state: stateLineComment},
+}, {
+	name:          "postgres hash",
+	dialect:       Postgres{},
+	text:          "SELECT 1 # ",
+	expectContext: context{},
+}, {
+	name:          "block comment",
+	dialect:       Postgres{},
+	text:          "SELECT /* a /* b */",
+	expectContext: context{state: stateBlockComment, depth: 1},
+}, {
+	name:          "closed block comment",
+	dialect:       Postgres{},
+	text:          "SELECT /* a /* b */ c */ ",
+	expectContext: context{},
+}, {
+	name:          "unnested block comment",
+	dialect:       MySQL{},
+	text:          "SELECT /* a /* b */ ",
+	expectContext: context{},
+}, {
+	name:          "dollar quote",
+	dialect:       Postgres{},
+	text:          "SELECT $fn$ a $$ 'b",
+	expectContext: context{state: stateDollarQuote, tag: "$fn$"},
+}, {
+	name:          "closed dollar quote",
+	dialect:       Postgres{},
+	text:          "SELECT $$ a $fn$ 'b$$, ",
+	expectContext: context{},
+}, {
+	name:          "positional parameter",
+	dialect:       Postgres{},
+	text:          "SELECT $1$",
+	expectContext: context{},
+}, {
+	name:          "dollar in identifier",
+	dialect:       Postgres{},
+	text:          "SELECT a$b$",
+	expectContext: context{},
+}, {
+	name:          "mysql dollar",
+	dialect:       MySQL{},
+	text:          "SELECT $$",
+	expectContext: context{}}, {
+	name:          "mysql pending dash",
+	dialect:       MySQL{},
+	text:          "SELECT 1--",
+	expectContext: context{pendingDash: true},
+}, {
+	name:          "mysql dash comment",
+	dialect:       MySQL{},
+	text:          "SELECT 1-- a",
+	expectContext: context{state: stateLineComment},
+}, {
+	name:          "postgres dash comment",
+	dialect:       Postgres{},
+	text:          "SELECT 1--",
+	expectContext: context{state: stateLineComment},
+}, {
+	name:          "oracle pending q quote delimiter",
+	dialect:       Oracle{},
+	text:          "SELECT q'",
+	expectContext: context{state: stateQuotedString},
+}, {
+	name:          "oracle q quote",
+	dialect:       Oracle{},
+	text:          "SELECT q'<a'",
+	expectContext: context{state: stateQuotedString, tag: ">'"},
+}, {
+	name:          "oracle not q quote",
+	dialect:       Oracle{},
+	text:          "SELECT seq'",
+	expectContext: context{state: stateString, delim: '\''},
+}, {
+	name:          "bigquery triple quote",
+	dialect:       BigQuery{},
+	text:          `SELECT """a\`,
+	expectContext: context{state: stateQuotedString, tag: `"""`, backslash: true, pendingEscape: true},
+}, {
+	name:          "sqlite no escape strings",
+	dialect:       SQLite{},
+	text:          `SELECT E'a\'`,
+	expectContext: context{},
+}}
+
+func TestLex(t *testing.T) {
+	for _, test := range lexTests {
+		t.Run(test.name, func(t *testing.T) {
+			c := syntaxOf(test.dialect).lex(context{}, []byte(test.text))
+			qt.Check(t, c, qt.Equals, test.expectContext)
+		})
+	}
+}
+
+func TestLexPendingEscape(t *testing.T) {
+	s := syntaxOf(Postgres{})
+	c := s.lex(context{}, []byte(`SELECT E'a\`))
+	qt.Assert(t, c.pendingEscape, qt.IsTrue)
+
+	// The escaped quote at the start of the next text does not end
+	// the string.
+	c = s.lex(c, []byte(`' AND b = 1`))
+	qt.Check(t, c, qt.Equals, context{state: stateString, delim: '\'', backslash: true})
+}
@@ -0,0 +1,173 @@
+package sqltemplate
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A Dialect describes the variant of SQL understood by a particular
+// database.
+type Dialect interface {
+	// Name returns the name of the dialect, for example "postgres".
+	Name() string
+
+	// Literal formats v as an SQL literal. A template's sqlliteral
+	// function is the Literal method of its dialect, so Literal must
+	// follow the rules given for sqlliteral in the package documentation.
+	Literal(v interface{}) (RawSQL, error)
+
+	// QuoteIdentifier formats name as a quoted identifier.
+	QuoteIdentifier(name string) (RawSQL, error)
+
+	// Bool returns the SQL representation of the boolean value b. An
+	// error is returned if booleans cannot be represented.
+	Bool(b bool) (RawSQL, error)
+
+	// Null returns the SQL representation of the NULL value.
+	Null() RawSQL
+
+	// Placeholders returns the placeholder style used by database
+	// drivers for the dialect.
+	Placeholders() PlaceholderStyle
+
+	// Supports reports whether the dialect supports the given feature.
+	Supports(f Feature) bool
+}
+
+// A Feature identifies an optional part of SQL that a Dialect might
+// support.
+type Feature int
+
+const (
+	// FeatureBooleans is supported by dialects that have the boolean
+	// literals TRUE and FALSE.
+	FeatureBooleans Feature = iota
+
+	// FeatureArrays is supported by dialects that have array values.
+	FeatureArrays
+
+	// FeatureDollarQuoting is supported by dialects that allow string
+	// constants to be written between dollar-quote tags, such as
+	// $tag$text$tag$.
+	FeatureDollarQuoting
+
+	// FeatureBackslashEscapes is supported by dialects that treat
+	// backslashes in string literals as escape characters.
+	FeatureBackslashEscapes
+
+	// FeatureEscapeStrings is supported by dialects in which string
+	// constants prefixed with E, such as E'a\tb', treat backslashes as
+	// escape characters.
+	FeatureEscapeStrings
+
+	// FeatureHashComments is supported by dialects in which # starts a
+	// comment that continues to the end of the line.
+	FeatureHashComments
+
+	// FeatureNestedComments is supported by dialects in which block
+	// comments can be nested, such as /* a /* b */ c */.
+	FeatureNestedComments
+
+	// FeatureDashCommentSpace is supported by dialects in which -- only
+	// starts a comment when it is followed by whitespace or a control
+	// character.
+	FeatureDashCommentSpace
+
+	// FeatureQQuoting is supported by dialects that allow string
+	// constants to be written with alternative quoting, such as
+	// q'[text]'.
+	FeatureQQuoting
+
+	// FeatureTripleQuoting is supported by dialects that allow string
+	// constants to be written between triple quotes, such as
+	// '''text'''.
+	FeatureTripleQuoting
+
+	// FeatureBackslashQuotes is supported by dialects in which a quote
+	// inside a string literal can only be escaped with a backslash, such
+	// as 'it\'s', and not by doubling it.
+	FeatureBackslashQuotes
+
+	// FeatureBackslashIdentifiers is supported by dialects that treat
+	// backslashes in quoted identifiers as escape characters.
+	FeatureBackslashIdentifiers
+
+	// FeatureBacktickIdentifiers is supported by dialects in which
+	// identifiers may also be quoted with backticks, such as `name`.
+	FeatureBacktickIdentifiers
+
+	// FeatureBracketIdentifiers is supported by dialects in which
+	// identifiers may also be quoted with square brackets, such as
+	// [name]. The closing bracket cannot be escaped in these
+	// identifiers.
+	FeatureBracketIdentifiers
+)
+
+// An SQLLiteraler is a value that formats itself as an SQL literal. The
+// Literal methods of the dialects in this package use SQLLiteral in
+// preference to any other formatting, including the Value method of
+// database/sql/driver.Valuer.
+//
+// A type can implement SQLLiteraler to produce a more exact literal than
+// is possible from a driver.Value, for example an enumeration value cast
+// to its enumerated type.
+type SQLLiteraler interface {
+	// SQLLiteral formats the value as a literal in the given dialect.
+	SQLLiteral(d Dialect) (RawSQL, error)
+}
+
+var (
+	literalerType = reflect.TypeOf((*SQLLiteraler)(nil)).Elem()
+	valuerType    = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// indirect resolves v to the value that should be formatted by the
+// Literal method of d. If v implements SQLLiteraler then the result of
+// calling SQLLiteral is returned, otherwise if v implements driver.Valuer
+// then the result of calling Value is returned, otherwise pointers are
+// followed until a non-pointer value is found. Nil pointers resolve to
+// nil.
+func indirect(d Dialect, v interface{}) (interface{}, error) {
+	for {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() && !nilReceiver(rv.Type()) {
+			return nil, nil
+		}
+		if l, ok := v.(SQLLiteraler); ok {
+			return l.SQLLiteral(d)
+		}
+		if dv, ok := v.(driver.Valuer); ok {
+			return dv.Value()
+		}
+		if rv.Kind() != reflect.Ptr {
+			return v, nil
+		}
+		v = rv.Elem().Interface()
+	}
+}
+
+// nilReceiver reports whether the method indirect would call on a nil
+// pointer of type t has a pointer receiver, and so can be called without
+// panicking.
+func nilReceiver(t reflect.Type) bool {
+	for _, it := range []reflect.Type{literalerType, valuerType} {
+		if t.Implements(it) {
+			return !t.Elem().Implements(it)
+		}
+	}
+	return false
+}
+
+// escapeBytes returns b with every byte written as a \xHH escape
+// sequence, as used in the string and binary literals of a number of
+// dialects.
+func escapeBytes(b []byte) string {
+	var sb strings.Builder
+	sb.Grow(4 * len(b))
+	for _, c := range b {
+		fmt.Fprintf(&sb, `\x%02X`, c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package sqltemplate
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+var indirectTests = []struct {
+	name        string
+	value       interface{}
+	expectValue interface{}
+}{{
+	name:        "nil",
+	value:       nil,
+	expectValue: nil,
+}, {
+	name:        "value",
+	value:       "test",
+	expectValue: "test",
+}, {
+	name:        "pointer",
+	value:       newString("test"),
+	expectValue: "test",
+}, {
+	name:        "nil pointer",
+	value:       (*string)(nil),
+	expectValue: nil,
+}, {
+	name: "pointer to pointer",
+	value: func() **string {
+		s := newString("test")
+		return &s
+	}(),
+	expectValue: "test",
+}, {
+	name:        "valuer",
+	value:       sql.NullString{Valid: true, String: "test"},
+	expectValue: "test",
+}, {
+	name:        "valuer pointer",
+	value:       &sql.NullString{Valid: true, String: "test"},
+	expectValue: "test",
+}, {
+	name:        "nil valuer pointer",
+	value:       (*sql.NullString)(nil),
+	expectValue: nil,
+}, {
+	name:        "nil pointer receiver valuer",
+	value:       (*ptrValuer)(nil),
+	expectValue: "nil receiver",
+}, {
+	name:        "literaler",
+	value:       testEnum("active"),
+	expectValue: RawSQL("'active'::status"),
+}, {
+	name:        "literaler pointer",
+	value:       func() *testEnum { e := testEnum("active"); return &e }(),
+	expectValue: RawSQL("'active'::status"),
+}, {
+	name:        "nil literaler pointer",
+	value:       (*testEnum)(nil),
+	expectValue: nil,
+}, {
+	name:        "nil pointer receiver literaler",
+	value:       (*ptrLiteraler)(nil),
+	expectValue: RawSQL("nil receiver"),
+}}
+
+func TestIndirect(t *testing.T) {
+	for _, test := range indirectTests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := indirect(Postgres{}, test.value)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, v, qt.Equals, test.expectValue)
+		})
+	}
+}
+
+type ptrValuer struct{}
+
+func (v *ptrValuer) Value() (driver.Value, error) {
+	if v == nil {
+		return "nil receiver", nil
+	}
+	return "non-nil receiver", nil
+}
+
+// testEnum implements both SQLLiteraler and driver.Valuer.
+type testEnum string
+
+func (e testEnum) SQLLiteral(d Dialect) (RawSQL, error) {
+	s, err := d.Literal(string(e))
+	if err != nil {
+		return "", err
+	}
+	if d.Name() == "postgres" {
+		s += "::status"
+	}
+	return s, nil
+}
+
+func (e testEnum) Value() (driver.Value, error) {
+	return string(e), nil
+}
+
+type ptrLiteraler struct{}
+
+func (l *ptrLiteraler) SQLLiteral(Dialect) (RawSQL, error) {
+	if l == nil {
+		return "nil receiver", nil
+	}
+	return "non-nil receiver", nil
+}
+
+func TestSQLLiteralerDialects(t *testing.T) {
+	for _, d := range []Dialect{Postgres{}, MySQL{}, SQLite{}, SQLServer{}, Oracle{}, ClickHouse{}, DuckDB{}, BigQuery{}} {
+		s, err := d.Literal(testEnum("it's"))
+		qt.Assert(t, err, qt.IsNil)
+		expect, err := d.Literal("it's")
+		qt.Assert(t, err, qt.IsNil)
+		if d.Name() == "postgres" {
+			expect += "::status"
+		}
+		qt.Check(t, s, qt.Equals, expect, qt.Commentf("%s", d.Name()))
+	}
+}
@@ -6,16 +6,44 @@
 // This package wraps the templates created by text/template such that the
 // result of any pipeline is encoded using the sqlliteral function.
 //
-// Unlike the html/template package no attempt is made to derive semantic
-// understanding of the template and encode values differently depending on
-// where they are used. Templates in this package will always encode the
-// same value in the same way regardless of context.
+// By default, unlike the html/template package, no attempt is made to
+// derive semantic understanding of the template and encode values
+// differently depending on where they are used. Templates will always
+// encode the same value in the same way regardless of context. Contextual
+// escaping can be enabled with the "escape=contextual" option, see
+// below.
+//
+// The SQL text of a template is lexed when it is parsed, using the rules
+// of the template's Dialect, so that actions in positions where a literal
+// can never be correct are reported as errors. These positions are inside
+// string literals, quoted identifiers, dollar-quoted strings, strings with
+// custom delimiters such as Oracle's q'[...]' and BigQuery's triple-quoted
+// strings, and comments. Which of these forms are recognized, and how
+// comments start, is determined by the Features the Dialect supports.
+// BigQuery raw strings are lexed as ordinary string literals. For example
+// parsing
+//
+//	SELECT * FROM users WHERE name = '{{.Name}}'
+//
+// fails, as the literal produced for .Name would be nested inside another
+// string literal. The branches of {{if}} and {{with}} actions must end in
+// the same context, the body of a {{range}} action, and any {{break}} or
+// {{continue}} actions in it, must be in the context the body started in,
+// and {{template}} actions must be in SQL code. Templates must end in SQL
+// code or a line comment, and a template that ends in a line comment, or
+// directly after a - or --, cannot be called with a {{template}} action.
+// In dialects where -- always starts a comment, a space is written before
+// the result of an action that directly follows a - if the result starts
+// with -, such as a negative number, and {{template}} actions must not
+// directly follow a -. After an error is reported by Parse none of the
+// associated templates can be executed.
 //
 // # The sqlliteral function
 //
 // The sqlliteral template function must be a function of the form func(v
-// interface{}) (RawSQL, error), the default implementation is
-// PostgresLiteral.
+// interface{}) (RawSQL, error). By default it is the Literal method of the
+// template's Dialect, which is Postgres unless changed with
+// Template.WithDialect. It may also be replaced using Template.Funcs.
 //
 // Implementations of sqlliteral must support any type that implements
 // database/sql/driver.Valuer along with the types documented to make up
@@ -29,5 +57,108 @@
 //	string
 //	time.Time
 //
-// Additional types may also be supported.
+// Additional types may also be supported. Values that implement
+// SQLLiteraler should be formatted using their SQLLiteral method in
+// preference to any other method.
+//
+// # Functions
+//
+// In addition to sqlliteral and the functions defined by text/template,
+// the following functions are available in templates:
+//
+//	in
+//		Returns its argument, which must be a slice or array, as a
+//		parenthesized list of values suitable for use with the SQL IN
+//		operator. Each element is formatted using sqlliteral, for
+//		example {{in .IDs}} might produce (1, 2, 3). An empty list
+//		causes an error, unless the "emptyin=null" option is set in
+//		which case (NULL) is produced.
+//	json
+//		Returns its argument wrapped in a JSON value, so that it is
+//		formatted as a JSON document, for example {{json .Payload}}.
+//
+// # Trusted functions
+//
+// Functions added with Template.Funcs are not trusted to produce SQL, if
+// one returns a RawSQL value then execution stops with an error. Functions
+// that build SQL fragments must instead be added with
+// Template.TrustedFuncs. The in and json functions are trusted.
+//
+// Templates that have the "rawsql=deny" option set before they are parsed
+// also reject RawSQL values in template data, wherever they appear in the
+// value being formatted. This ensures that every RawSQL value written to
+// the output was produced by a trusted function. The SQL type names held
+// by Array, Range, Multirange and Row values are written to the output
+// too, so these must always be valid type names, whatever the options.
+//
+// # Trusted SQL
+//
+// A RawSQL value can be created from any string, including untrusted
+// input. A TrustedSQL value can only be created from an untyped string
+// constant, using TrustedSQLFromConstant, or by combining other TrustedSQL
+// values, using TrustedSQLConcat and TrustedSQLJoin:
+//
+//	order := sqltemplate.TrustedSQLFromConstant("ORDER BY name")
+//	if newestFirst {
+//		order = sqltemplate.TrustedSQLFromConstant("ORDER BY created DESC")
+//	}
+//
+// TrustedSQL values are inserted into the template output verbatim by all
+// dialects, and are always allowed in template data, even in templates
+// with the "rawsql=deny" option set. The unsafesql package can create
+// TrustedSQL values from arbitrary strings where this is unavoidable;
+// uses of it should be audited carefully.
+//
+// # Contextual escaping
+//
+// In templates that have the "escape=contextual" option set before they
+// are parsed, the result of each pipeline is escaped according to where
+// it appears:
+//
+//	SQL code
+//		The result is formatted using sqlliteral, as usual.
+//	Inside a string literal
+//		The result is converted to text and escaped so that it forms
+//		part of the string, for example '%{{.}}%' might produce
+//		'%it''s%'. In dialects, or string constants, that treat
+//		backslashes as escape characters, backslashes are also escaped,
+//		and in dialects that support FeatureBackslashQuotes quotes are
+//		escaped with a backslash rather than by doubling them.
+//	Inside a quoted identifier
+//		The result is converted to text and escaped so that it forms
+//		part of the identifier, for example "tenant_{{.ID}}". In
+//		dialects that support FeatureBackslashIdentifiers backslashes
+//		and the closing delimiter are escaped with a backslash.
+//
+// Actions inside comments, dollar-quoted strings, strings with custom
+// delimiters or identifiers quoted with square brackets in dialects that
+// support FeatureBracketIdentifiers are still an error, as are actions
+// that directly follow a backslash that escapes the next character of a
+// string literal or quoted identifier.
+//
+// Values are converted to text in the same way as database/sql converts
+// query arguments, so driver.Valuer implementations are used. NULL values
+// cannot be used inside string literals or quoted identifiers. Values are
+// always written inline, even when a template is executed with
+// ExecuteArgs.
+//
+// # Query arguments
+//
+// Templates executed with ExecuteArgs do not format pipeline results as
+// literals. Instead each value is returned as a query argument and a
+// placeholder is written in its place. Values of type RawSQL and
+// Identifier cannot be passed as arguments and are still formatted using
+// the sqlliteral function.
+//
+// Slices, arrays and maps are not accepted by database drivers. With the
+// Postgres dialect they are passed in the PostgreSQL text input format,
+// for example a []int is passed as an Array value. With other dialects
+// they are formatted using the sqlliteral function.
+// Also with the Postgres dialect, time.Duration values and network
+// addresses are passed in their text form, as they are when formatted as
+// literals.
+//
+// The form of the placeholders depends on the database driver in use. It
+// is chosen with a PlaceholderStyle set using either Template.Placeholders
+// or the "placeholder" option.
 package sqltemplate
@@ -0,0 +1,133 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DuckDB is the Dialect for the DuckDB database.
+type DuckDB struct{}
+
+// Name implements Dialect.
+func (DuckDB) Name() string {
+	return "duckdb"
+}
+
+// QuoteIdentifier implements Dialect, see
+// https://duckdb.org/docs/sql/keywords_and_identifiers.
+func (DuckDB) QuoteIdentifier(name string) (RawSQL, error) {
+	return RawSQL(`"` + strings.ReplaceAll(name, `"`, `""`) + `"`), nil
+}
+
+// Bool implements Dialect.
+func (DuckDB) Bool(b bool) (RawSQL, error) {
+	if b {
+		return RawSQL("TRUE"), nil
+	}
+	return RawSQL("FALSE"), nil
+}
+
+// Null implements Dialect.
+func (DuckDB) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, DuckDB drivers use
+// DollarPlaceholders.
+func (DuckDB) Placeholders() PlaceholderStyle {
+	return DollarPlaceholders
+}
+
+// Supports implements Dialect. DuckDB supports FeatureBooleans,
+// FeatureArrays, FeatureDollarQuoting, FeatureEscapeStrings and
+// FeatureNestedComments.
+func (DuckDB) Supports(f Feature) bool {
+	switch f {
+	case FeatureBooleans, FeatureArrays, FeatureDollarQuoting, FeatureEscapeStrings, FeatureNestedComments:
+		return true
+	}
+	return false
+}
+
+// DuckDBLiteral formats the value v as a literal suitable for use in
+// queries used with the DuckDB database. It is equivalent to calling the
+// Literal method of a DuckDB value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  Either the SQL keyword TRUE, or FALSE.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  If the value represents +Inf, -Inf or NaN then the literal will be
+//	  'inf'::DOUBLE, '-inf'::DOUBLE or 'nan'::DOUBLE respectively.
+//	  Otherwise the %g encoding provided by fmt.Printf is used.
+//	string
+//	  A string literal.
+//	[]byte
+//	  A BLOB literal with every byte written as a \xHH escape sequence,
+//	  for example BLOB '\x74\x65\x73\x74'.
+//	time.Time
+//	  A TIMESTAMPTZ literal with microsecond precision.
+//	Identifier
+//	  A quoted identifier, see
+//	  https://duckdb.org/docs/sql/keywords_and_identifiers.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func DuckDBLiteral(v interface{}) (RawSQL, error) {
+	return DuckDB{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// DuckDBLiteral.
+func (d DuckDB) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if v1 == nil {
+			return d.Null(), nil
+		}
+		return RawSQL(`BLOB '` + escapeBytes(v1) + `'`), nil
+	case float64:
+		switch {
+		case math.IsInf(v1, 1):
+			return RawSQL("'inf'::DOUBLE"), nil
+		case math.IsInf(v1, -1):
+			return RawSQL("'-inf'::DOUBLE"), nil
+		case math.IsNaN(v1):
+			return RawSQL("'nan'::DOUBLE"), nil
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		return RawSQL(`'` + strings.ReplaceAll(v1, `'`, `''`) + `'`), nil
+	case time.Time:
+		return RawSQL(`TIMESTAMPTZ '` + v1.Format("2006-01-02 15:04:05.999999-07:00") + `'`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
@@ -1,57 +1,452 @@
 package sqltemplate
 
-import "text/template/parse"
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Names of the functions added to pipelines that are in the body of a
+// string literal or a quoted identifier in contextual mode. Each takes the
+// closing delimiter as its first argument.
+const (
+	stringEscaper          = "_sqltemplate_stringescaper"
+	backslashStringEscaper = "_sqltemplate_backslashstringescaper"
+	identEscaper           = "_sqltemplate_identescaper"
+	backslashIdentEscaper  = "_sqltemplate_backslashidentescaper"
+)
+
+// minusSpacer is the name of the function added after sqlliteral to
+// pipelines that directly follow a - in SQL code. It adds a space before
+// a result that starts with -, such as a negative number, so that the two
+// do not start a comment.
+const minusSpacer = "_sqltemplate_minusspacer"
+
+// rawSQLChecker is the name of the function added before sqlliteral to
+// pipelines that do not end with a trusted function when RawSQL values
+// are not allowed in template data.
+const rawSQLChecker = "_sqltemplate_rawsqlchecker"
+
+// escapeTemplate escapes all the templates defined in a template. If
+// escaping fails then the error is recorded in the name space so that the
+// partially escaped templates cannot be executed.
+func (ns *nameSpace) escapeTemplate(t *template.Template) error {
+	for _, tmpl := range t.Templates() {
+		if _, err := ns.escapeTree(tmpl.Tree); err != nil {
+			ns.escapeErr = err
+			return err
+		}
+	}
+	if err := ns.checkCalls(t); err != nil {
+		ns.escapeErr = err
+		return err
+	}
+	return nil
+}
+
+// checkCalls checks that no template associated with t uses a
+// {{template}} action to call a template that ends inside a line comment,
+// as the comment would continue into the text following the action, or
+// after a - or -- that the following text could turn into a comment. Such
+// templates can only be executed directly.
+func (ns *nameSpace) checkCalls(t *template.Template) error {
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+		e := escaper{ns: ns, tree: tmpl.Tree}
+		if err := e.checkCalls(t, tmpl.Tree.Root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 // escapeTree adds additional "sqlliteral" function calls to the end of all
 // pipelines. This ensures that inserted variables are formatted as
 // appropriate SQL literals. This function is idempotent so an "sqlliteral"
 // function call is only added to the end of pipelines where there isn't
 // one already.
-func escapeTree(t *parse.Tree) *parse.Tree {
+//
+// The SQL text around each pipeline is lexed, and pipelines that are not
+// in SQL code, where a literal can never be correct, result in an error.
+// If the name space uses contextual escaping then pipelines that are
+// inside string literals or quoted identifiers are instead escaped for
+// that context.
+//
+// The context at the end of the template is returned. The end of the
+// template ends a line comment, so a template may end inside one.
+func (ns *nameSpace) escapeTree(t *parse.Tree) (context, error) {
 	if t.Root == nil {
-		return t
+		return context{}, nil
 	}
-	escapeNode(t, t.Root)
-	return t
+	e := escaper{
+		ns:         ns,
+		tree:       t,
+		syntax:     syntaxOf(ns.dialect),
+		contextual: ns.contextual,
+	}
+	c, err := e.escapeList(context{}, t.Root)
+	if err == nil && c.state != stateCode && c.state != stateLineComment {
+		err = fmt.Errorf("sqltemplate: %s: template ends inside %s", t.Name, c.state)
+	}
+	return c, err
+}
+
+// An escaper adds escaping function calls to the pipelines in a tree.
+type escaper struct {
+	ns         *nameSpace
+	tree       *parse.Tree
+	syntax     syntax
+	contextual bool
+
+	// loops holds the contexts at the start of the bodies of the
+	// enclosing range loops, innermost last.
+	loops []context
+}
+
+// escapeList escapes the nodes in l, which starts in context c, and
+// returns the context at the end of l.
+func (e *escaper) escapeList(c context, l *parse.ListNode) (context, error) {
+	if l == nil {
+		return c, nil
+	}
+	for _, n := range l.Nodes {
+		var err error
+		if c, err = e.escapeNode(c, n); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
 }
 
-// escapeNode processes the given node in the given tree for adding
-// sqlliteral function calls to the end of pipelines.
-func escapeNode(t *parse.Tree, n parse.Node) {
-	switch v := n.(type) {
+// escapeNode escapes the node n, which starts in context c, and returns
+// the context at the end of n.
+func (e *escaper) escapeNode(c context, n parse.Node) (context, error) {
+	switch n := n.(type) {
+	case *parse.TextNode:
+		return e.syntax.lex(c, n.Text), nil
 	case *parse.ActionNode:
-		escapeNode(t, v.Pipe)
+		if err := e.escapeAction(c, n); err != nil {
+			return c, err
+		}
+		if len(n.Pipe.Decl) == 0 {
+			// A literal never starts with whitespace, so -- before
+			// an action does not start a comment, and a space is
+			// added to a literal starting with - that follows -.
+			c.pendingDash = false
+			c.pendingMinus = false
+		}
+		return c, nil
 	case *parse.IfNode:
-		escapeNode(t, v.List)
-		escapeNode(t, v.ElseList)
+		return e.escapeBranch(c, n, &n.BranchNode, false)
+	case *parse.RangeNode:
+		return e.escapeBranch(c, n, &n.BranchNode, true)
+	case *parse.WithNode:
+		return e.escapeBranch(c, n, &n.BranchNode, false)
+	case *parse.BreakNode, *parse.ContinueNode:
+		// Execution continues from the start or the end of the
+		// loop body, which are in the same context.
+		if start := e.loops[len(e.loops)-1]; c != start {
+			return c, e.errorf(n, "%s appears inside %s but the loop body starts in %s", n, c, start)
+		}
+	case *parse.TemplateNode:
+		if c.state != stateCode {
+			return c, e.errorf(n, "%s appears inside %s", n, c.state)
+		}
+		if c.pendingDash {
+			return c, e.errorf(n, "%s appears after --, which might start a comment", n)
+		}
+		if c.pendingMinus {
+			return c, e.errorf(n, "%s appears after -, which might start a comment", n)
+		}
+	}
+	return c, nil
+}
+
+// escapeAction adds the escaping function appropriate to context c to the
+// pipeline of n.
+func (e *escaper) escapeAction(c context, n *parse.ActionNode) error {
+	if len(n.Pipe.Decl) > 0 {
+		// If the pipe sets variables then don't escape it.
+		return nil
+	}
+	if c.pendingEscape {
+		// The first character of the action's output would be
+		// escaped by the backslash, so it cannot be escaped safely.
+		return e.errorf(n, "%s follows a backslash inside %s", n, c.state)
+	}
+	switch c.state {
+	case stateCode:
+		e.appendCommand(n.Pipe, "sqlliteral")
+		if e.ns.denyRawSQL {
+			e.checkRawSQL(n.Pipe)
+		}
+		if c.pendingMinus {
+			// A literal starting with - would make -- and
+			// so start a comment.
+			e.appendCommand(n.Pipe, minusSpacer)
+		}
+		return nil
+	case stateString:
+		if !e.contextual {
+			break
+		}
+		if c.backslash {
+			quote := string(c.delim) + string(c.delim)
+			if e.syntax.backslashQuotes {
+				quote = `\` + string(c.delim)
+			}
+			e.appendCommand(n.Pipe, backslashStringEscaper, string(c.delim), quote)
+		} else {
+			e.appendCommand(n.Pipe, stringEscaper, string(c.delim))
+		}
+		return nil
+	case stateIdentifier:
+		if !e.contextual || c.noEscape {
+			break
+		}
+		if c.backslash {
+			e.appendCommand(n.Pipe, backslashIdentEscaper, string(c.delim))
+		} else {
+			e.appendCommand(n.Pipe, identEscaper, string(c.delim))
+		}
+		return nil
+	}
+	return e.errorf(n, "%s appears inside %s", n, c.state)
+}
+
+// escapeBranch escapes the lists in b, which belong to the node n and
+// start in context c. All the lists must end in the same context, which
+// is returned. If loop is set then the lists must also end in context c,
+// as must any {{break}} or {{continue}} actions in the loop body.
+func (e *escaper) escapeBranch(c context, n parse.Node, b *parse.BranchNode, loop bool) (context, error) {
+	if loop {
+		e.loops = append(e.loops, c)
+	}
+	c1, err := e.escapeList(c, b.List)
+	if loop {
+		e.loops = e.loops[:len(e.loops)-1]
+	}
+	if err != nil {
+		return c, err
+	}
+	if loop && c1 != c {
+		return c, e.errorf(n, "loop body starts in %s but ends inside %s", c.state, c1.state)
+	}
+	c2, err := e.escapeList(c, b.ElseList)
+	if err != nil {
+		return c, err
+	}
+	if c1 != c2 {
+		return c, e.errorf(n, "branches end in different contexts: %s, %s", c1, c2)
+	}
+	return c1, nil
+}
+
+// checkCalls checks the {{template}} actions in n, which is part of a
+// template associated with t, using checkCalls.
+func (e *escaper) checkCalls(t *template.Template, n parse.Node) error {
+	switch n := n.(type) {
 	case *parse.ListNode:
-		if v == nil {
-			return
+		if n == nil {
+			return nil
 		}
-		for _, n := range v.Nodes {
-			escapeNode(t, n)
+		for _, n := range n.Nodes {
+			if err := e.checkCalls(t, n); err != nil {
+				return err
+			}
 		}
-	case *parse.PipeNode:
-		if len(v.Decl) > 0 {
-			// If the pipe sets variables then don't escape it.
+	case *parse.IfNode:
+		return e.checkBranchCalls(t, &n.BranchNode)
+	case *parse.RangeNode:
+		return e.checkBranchCalls(t, &n.BranchNode)
+	case *parse.WithNode:
+		return e.checkBranchCalls(t, &n.BranchNode)
+	case *parse.TemplateNode:
+		callee := t.Lookup(n.Name)
+		if callee == nil || callee.Tree == nil {
+			return nil
+		}
+		// The callee has already been escaped, so this only
+		// determines the context at its end.
+		c, err := e.ns.escapeTree(callee.Tree)
+		switch {
+		case err != nil:
+		case c.state == stateLineComment:
+			return e.errorf(n, "%s calls a template that ends inside %s", n, c.state)
+		case c != context{}:
+			// The text following the action could start a
+			// comment.
+			return e.errorf(n, "%s calls a template that ends in %s", n, c)
+		}
+	}
+	return nil
+}
+
+// checkBranchCalls checks the {{template}} actions in the lists of b.
+func (e *escaper) checkBranchCalls(t *template.Template, b *parse.BranchNode) error {
+	if err := e.checkCalls(t, b.List); err != nil {
+		return err
+	}
+	return e.checkCalls(t, b.ElseList)
+}
+
+// checkRawSQL adds a command calling the RawSQL checking function before
+// the final sqlliteral command of p, unless the command before it is a
+// call to a trusted function.
+func (e *escaper) checkRawSQL(p *parse.PipeNode) {
+	n := len(p.Cmds) - 1
+	if n < 1 || !isCall(p.Cmds[n], "sqlliteral") || len(p.Cmds[n].Args) != 1 {
+		return
+	}
+	if prev := p.Cmds[n-1]; isCall(prev, "sqlliteral") && len(prev.Args) == 2 {
+		// Check the argument of an explicit sqlliteral call, rather
+		// than its result, by moving the argument into the pipeline.
+		if _, ok := prev.Args[1].(*parse.NilNode); ok {
 			return
 		}
-		if len(v.Cmds) < 1 {
+		prev.Args = prev.Args[1:]
+	}
+	if id, ok := p.Cmds[n-1].Args[0].(*parse.IdentifierNode); ok {
+		switch {
+		case id.Ident == rawSQLChecker:
+			// The pipeline has already been checked.
+			return
+		case e.ns.trusted[id.Ident]:
 			return
+		case id.Ident == "in" || id.Ident == "json":
+			if !e.ns.overridden[id.Ident] {
+				return
+			}
 		}
-		cmd := v.Cmds[len(v.Cmds)-1]
-		if len(cmd.Args) == 1 && cmd.Args[0].Type() == parse.NodeIdentifier && cmd.Args[0].(*parse.IdentifierNode).Ident == "sqlliteral" {
+	}
+	cmd := &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      p.Cmds[n].Pos,
+		Args:     []parse.Node{parse.NewIdentifier(rawSQLChecker).SetTree(e.tree).SetPos(p.Cmds[n].Pos)},
+	}
+	p.Cmds = append(p.Cmds[:n], cmd, p.Cmds[n])
+}
+
+// isCall reports whether cmd calls the named function.
+func isCall(cmd *parse.CommandNode, name string) bool {
+	id, ok := cmd.Args[0].(*parse.IdentifierNode)
+	return ok && id.Ident == name
+}
+
+// appendCommand adds a command calling the named function with the given
+// string arguments to the end of p, unless p already ends with an
+// escaping function. A pipeline ending with sqlliteral is only escaped
+// for SQL code, so other functions are still added after it.
+func (e *escaper) appendCommand(p *parse.PipeNode, name string, args ...string) {
+	if len(p.Cmds) < 1 {
+		return
+	}
+	cmd := p.Cmds[len(p.Cmds)-1]
+	if id, ok := cmd.Args[0].(*parse.IdentifierNode); ok {
+		switch id.Ident {
+		case "sqlliteral":
+			// A literal is only escaped for SQL code.
+			if len(cmd.Args) == 1 && name == "sqlliteral" {
+				return
+			}
+		case stringEscaper, backslashStringEscaper, identEscaper, backslashIdentEscaper, minusSpacer:
 			return
 		}
-		v.Cmds = append(v.Cmds, &parse.CommandNode{
-			NodeType: parse.NodeCommand,
-			Args:     []parse.Node{parse.NewIdentifier("sqlliteral").SetTree(t).SetPos(cmd.Pos)},
+	}
+	nodes := []parse.Node{parse.NewIdentifier(name).SetTree(e.tree).SetPos(cmd.Pos)}
+	for _, arg := range args {
+		nodes = append(nodes, &parse.StringNode{
+			NodeType: parse.NodeString,
+			Pos:      cmd.Pos,
+			Quoted:   fmt.Sprintf("%q", arg),
+			Text:     arg,
 		})
-	case *parse.RangeNode:
-		escapeNode(t, v.List)
-		escapeNode(t, v.ElseList)
-	case *parse.WithNode:
-		escapeNode(t, v.List)
-		escapeNode(t, v.ElseList)
 	}
+	p.Cmds = append(p.Cmds, &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      cmd.Pos,
+		Args:     nodes,
+	})
+}
+
+// errorf returns an error describing a problem with the node n. The error
+// includes the location of n and, if it differs from the name of the file
+// or text it was parsed from, the name of the template.
+func (e *escaper) errorf(n parse.Node, format string, args ...interface{}) error {
+	loc, _ := e.tree.ErrorContext(n)
+	msg := fmt.Sprintf(format, args...)
+	if e.tree.Name != e.tree.ParseName {
+		msg += fmt.Sprintf(" in template %q", e.tree.Name)
+	}
+	return fmt.Errorf("sqltemplate: %s: %s", loc, msg)
+}
+
+// spaceMinus implements the minus spacer template function.
+func spaceMinus(s RawSQL) RawSQL {
+	if strings.HasPrefix(string(s), "-") {
+		return " " + s
+	}
+	return s
+}
+
+// escapeString implements the string escaper template function, which
+// formats v as text inside a string literal that ends with delim.
+func escapeString(delim string, v interface{}) (RawSQL, error) {
+	s, err := escapeText(v, "a string literal")
+	if err != nil {
+		return "", err
+	}
+	return RawSQL(strings.ReplaceAll(s, delim, delim+delim)), nil
+}
+
+// escapeBackslashString implements the backslash string escaper template
+// function, which formats v as text inside a string literal that ends
+// with delim and in which backslashes are escape characters. Each delim
+// in the text is replaced with quote.
+func escapeBackslashString(delim, quote string, v interface{}) (RawSQL, error) {
+	s, err := escapeText(v, "a string literal")
+	if err != nil {
+		return "", err
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return RawSQL(strings.ReplaceAll(s, delim, quote)), nil
+}
+
+// escapeIdentifier implements the identifier escaper template function,
+// which formats v as text inside a quoted identifier that ends with
+// delim.
+func escapeIdentifier(delim string, v interface{}) (RawSQL, error) {
+	s, err := escapeText(v, "a quoted identifier")
+	if err != nil {
+		return "", err
+	}
+	return RawSQL(strings.ReplaceAll(s, delim, delim+delim)), nil
+}
+
+// escapeBackslashIdentifier implements the backslash identifier escaper
+// template function, which formats v as text inside a quoted identifier
+// that ends with delim and in which backslashes are escape characters.
+func escapeBackslashIdentifier(delim string, v interface{}) (RawSQL, error) {
+	s, err := escapeText(v, "a quoted identifier")
+	if err != nil {
+		return "", err
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return RawSQL(strings.ReplaceAll(s, delim, `\`+delim)), nil
+}
+
+// escapeText converts v to the text that is escaped for use inside the
+// given part of the SQL text.
+func escapeText(v interface{}, where string) (string, error) {
+	s, ok, err := textValue(v)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("cannot use NULL inside %s", where)
+	}
+	return s, nil
 }
@@ -1,8 +1,12 @@
 package sqltemplate
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"text/template"
 	"text/template/parse"
 
 	qt "github.com/frankban/quicktest"
@@ -17,14 +21,35 @@ some text
 {{range .}}{{.}}{{else}}{{.}}{{end}}
 {{with "test"}}{{.}}{{end}}
 {{with "test"}}{{.}}{{else}}{{end}}
+1-{{.}}
 `
 	mt, err := parse.Parse("", text, "{{", "}}", nil)
 	qt.Assert(t, err, qt.IsNil)
 
+	ns := newNameSpace()
 	t1 := mt[""]
-	escapeTree(t1)
+	_, err = ns.escapeTree(t1)
+	qt.Assert(t, err, qt.IsNil)
 	t2 := t1.Copy()
-	escapeTree(t2)
+	_, err = ns.escapeTree(t2)
+	qt.Assert(t, err, qt.IsNil)
+
+	qt.Check(t, t1, qt.CmpEquals(cmp.Comparer(parseTreeComparer)), t2)
+}
+
+func TestEscapeTreeIdempotentDenyRawSQL(t *testing.T) {
+	mt, err := parse.Parse("", `{{.}} {{sqlliteral .}} {{json .}} {{. | print}}`, "{{", "}}", map[string]interface{}{"json": fmt.Sprint, "print": fmt.Sprint, "sqlliteral": fmt.Sprint})
+	qt.Assert(t, err, qt.IsNil)
+
+	ns := newNameSpace()
+	ns.denyRawSQL = true
+	t1 := mt[""]
+	_, err = ns.escapeTree(t1)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, t1.Root.String(), qt.Equals, `{{. | _sqltemplate_rawsqlchecker | sqlliteral}} {{. | _sqltemplate_rawsqlchecker | sqlliteral}} {{json . | sqlliteral}} {{. | print | _sqltemplate_rawsqlchecker | sqlliteral}}`)
+	t2 := t1.Copy()
+	_, err = ns.escapeTree(t2)
+	qt.Assert(t, err, qt.IsNil)
 
 	qt.Check(t, t1, qt.CmpEquals(cmp.Comparer(parseTreeComparer)), t2)
 }
@@ -62,3 +87,582 @@ func TestEscapeVarSettingPipe(t *testing.T) {
 	qt.Assert(t, err, qt.IsNil)
 	qt.Check(t, b.String(), qt.Equals, `'<~A~>'`)
 }
+
+var contextualTests = []struct {
+	name        string
+	dialect     Dialect
+	text        string
+	data        interface{}
+	expectSQL   string
+	expectError string
+}{{
+	name:      "code",
+	text:      `SELECT {{.}}`,
+	data:      "it's",
+	expectSQL: `SELECT 'it''s'`,
+}, {
+	name:      "string",
+	text:      `SELECT '{{.}}'`,
+	data:      "it's",
+	expectSQL: `SELECT 'it''s'`,
+}, {
+	name:      "string like pattern",
+	text:      `SELECT * FROM t WHERE a LIKE '%{{.}}%'`,
+	data:      "it's",
+	expectSQL: `SELECT * FROM t WHERE a LIKE '%it''s%'`,
+}, {
+	name:      "string number",
+	text:      `SELECT '{{.}}'`,
+	data:      42,
+	expectSQL: `SELECT '42'`,
+}, {
+	name:      "string raw sql",
+	text:      `SELECT '{{.}}'`,
+	data:      RawSQL("'; DROP TABLE t; --"),
+	expectSQL: `SELECT '''; DROP TABLE t; --'`,
+}, {
+	name:        "string nil",
+	text:        `SELECT '{{.}}'`,
+	data:        nil,
+	expectError: `template: :1:10: executing "" at <_sqltemplate_stringescaper "'">: error calling _sqltemplate_stringescaper: cannot use NULL inside a string literal`,
+}, {
+	name:      "escape string",
+	text:      `SELECT E'{{.}}'`,
+	data:      `it's \`,
+	expectSQL: `SELECT E'it''s \\'`,
+}, {
+	name:      "mysql string",
+	dialect:   MySQL{},
+	text:      `SELECT "{{.}}"`,
+	data:      `"\`,
+	expectSQL: `SELECT """\\"`,
+}, {
+	name:      "bigquery string",
+	dialect:   BigQuery{},
+	text:      `SELECT '{{.}}', "{{.}}"`,
+	data:      `it's "\`,
+	expectSQL: `SELECT 'it\'s "\\', "it's \"\\"`,
+}, {
+	name:      "explicit sqlliteral in string",
+	text:      `SELECT '{{. | sqlliteral}}'`,
+	data:      "x' OR 1=1 --",
+	expectSQL: `SELECT '''x'''' OR 1=1 --'''`,
+}, {
+	name:      "explicit sqlliteral in identifier",
+	text:      `SELECT * FROM "{{sqlliteral .}}"`,
+	data:      `a"b`,
+	expectSQL: `SELECT * FROM "'a""b'"`,
+}, {
+	name:      "bigquery identifier",
+	dialect:   BigQuery{},
+	text:      "SELECT * FROM `t_{{.}}`",
+	data:      "\\`",
+	expectSQL: "SELECT * FROM `t_\\\\\\``",
+}, {
+	name:        "clickhouse identifier after backslash",
+	dialect:     ClickHouse{},
+	text:        "SELECT * FROM `t_\\{{.}}` WHERE x = 1",
+	expectError: `sqltemplate: :1:20: {{.}} follows a backslash inside a quoted identifier`,
+}, {
+	name:      "sqlite backtick identifier",
+	dialect:   SQLite{},
+	text:      "SELECT * FROM `t_{{.}}`",
+	data:      "a`b",
+	expectSQL: "SELECT * FROM `t_a``b`",
+}, {
+	name:        "sqlite bracket identifier",
+	dialect:     SQLite{},
+	text:        `SELECT * FROM [t_{{.}}]`,
+	expectError: `sqltemplate: :1:19: {{.}} appears inside a quoted identifier`,
+}, {
+	name:      "negative after minus",
+	text:      `SELECT 1-{{.}} AND tenant = 1`,
+	data:      -5,
+	expectSQL: `SELECT 1- -5 AND tenant = 1`,
+}, {
+	name:      "positive after minus",
+	text:      `SELECT 1-{{.}}`,
+	data:      5,
+	expectSQL: `SELECT 1-5`,
+}, {
+	name:      "mysql negative after minus",
+	dialect:   MySQL{},
+	text:      `SELECT 1-{{.}}`,
+	data:      -5,
+	expectSQL: `SELECT 1--5`,
+}, {
+	name:      "mysql no backslash escapes",
+	dialect:   MySQL{NoBackslashEscapes: true},
+	text:      `SELECT '{{.}}'`,
+	data:      `'\`,
+	expectSQL: `SELECT '''\'`,
+}, {
+	name:      "identifier",
+	text:      `SELECT * FROM "tenant_{{.}}"`,
+	data:      `a"b`,
+	expectSQL: `SELECT * FROM "tenant_a""b"`,
+}, {
+	name:      "sql server identifier",
+	dialect:   SQLServer{},
+	text:      `SELECT * FROM [tenant_{{.}}]`,
+	data:      `a]b`,
+	expectSQL: `SELECT * FROM [tenant_a]]b]`,
+}, {
+	name:        "line comment",
+	text:        "SELECT 1 -- {{.}}\n",
+	expectError: `sqltemplate: :1:14: {{.}} appears inside a line comment`,
+}, {
+	name:        "block comment",
+	text:        "SELECT 1 /* {{.}} */",
+	expectError: `sqltemplate: :1:14: {{.}} appears inside a block comment`,
+}, {
+	name:        "dollar quote",
+	text:        "SELECT $$ {{.}} $$",
+	expectError: `sqltemplate: :1:12: {{.}} appears inside a dollar-quoted string`,
+}, {
+	name:      "after comment",
+	text:      "SELECT 1 -- it's\n, {{.}}",
+	data:      1,
+	expectSQL: "SELECT 1 -- it's\n, 1",
+}, {
+	name:      "if",
+	text:      `SELECT '{{if .}}{{.}}{{else}}none{{end}}'`,
+	data:      "a",
+	expectSQL: `SELECT 'a'`,
+}, {
+	name:        "if different contexts",
+	text:        `SELECT {{if .}}'{{.}}{{else}}{{.}}{{end}}'`,
+	expectError: `sqltemplate: :1:12: branches end in different contexts: a string literal, SQL code`,
+}, {
+	name:      "range",
+	text:      `SELECT '{{range .}}{{.}},{{end}}'`,
+	data:      []string{"a", "b'"},
+	expectSQL: `SELECT 'a,b'','`,
+}, {
+	name:        "range changes context",
+	text:        `SELECT {{range .}}'{{.}}{{end}}`,
+	expectError: `sqltemplate: :1:15: loop body starts in SQL code but ends inside a string literal`,
+}, {
+	name:      "range break",
+	text:      `SELECT {{range .}}{{if eq . "b"}}{{break}}{{end}}'{{.}}',{{end}}`,
+	data:      []string{"a", "b", "c"},
+	expectSQL: `SELECT 'a',`,
+}, {
+	name:        "break inside string",
+	text:        `SELECT {{range .L}}'a{{if .}}{{break}}{{end}}b'{{end}} = {{.Y}}`,
+	expectError: `sqltemplate: :1:31: {{break}} appears inside a string literal but the loop body starts in SQL code`,
+}, {
+	name:        "continue inside string",
+	text:        `SELECT {{range .L}}'a{{if .}}{{continue}}{{end}}b'{{end}} = {{.Y}}`,
+	expectError: `sqltemplate: :1:31: {{continue}} appears inside a string literal but the loop body starts in SQL code`,
+}, {
+	name:        "escape string after backslash",
+	text:        `SELECT E'a\{{.}}' AND tenant = 1`,
+	data:        "' OR 1=1 --",
+	expectError: `sqltemplate: :1:13: {{.}} follows a backslash inside a string literal`,
+}, {
+	name:        "mysql string after backslash",
+	dialect:     MySQL{},
+	text:        `SELECT 'a\{{.}}' AND tenant = 1`,
+	data:        "' OR 1=1 --",
+	expectError: `sqltemplate: :1:12: {{.}} follows a backslash inside a string literal`,
+}, {
+	name:      "escape string after escaped backslash",
+	text:      `SELECT E'a\\{{.}}' AND tenant = 1`,
+	data:      "' OR 1=1 --",
+	expectSQL: `SELECT E'a\\'' OR 1=1 --' AND tenant = 1`,
+}, {
+	name:      "string after backslash",
+	text:      `SELECT 'a\{{.}}'`,
+	data:      "'",
+	expectSQL: `SELECT 'a\'''`,
+}, {
+	name:        "escape string after backslash in branch",
+	text:        `SELECT E'a{{if .}}\{{end}}{{.}}'`,
+	data:        "'",
+	expectError: `sqltemplate: :1:15: branches end in different contexts: a string literal after a backslash, a string literal`,
+}, {
+	name:        "template in string",
+	text:        `{{define "a"}}a{{end}}SELECT '{{template "a"}}'`,
+	expectError: `sqltemplate: :1:41: {{template "a"}} appears inside a string literal`,
+}, {
+	name:        "unterminated string",
+	text:        `SELECT 'a`,
+	expectError: `sqltemplate: : template ends inside a string literal`,
+}, {
+	name:      "set variable in comment",
+	text:      `SELECT 1 /* {{$x := .}} */`,
+	data:      1,
+	expectSQL: `SELECT 1 /*  */`,
+}}
+
+func TestContextualEscaping(t *testing.T) {
+	for _, test := range contextualTests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl := New("").Option("escape=contextual")
+			if test.dialect != nil {
+				tmpl.WithDialect(test.dialect)
+			}
+			tmpl, err := tmpl.Parse(test.text)
+			if err != nil {
+				qt.Check(t, err, qt.ErrorMatches, regexp.QuoteMeta(test.expectError))
+				return
+			}
+			var sb strings.Builder
+			err = tmpl.Execute(&sb, test.data)
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, regexp.QuoteMeta(test.expectError))
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, sb.String(), qt.Equals, test.expectSQL)
+		})
+	}
+}
+
+func TestContextualEscapingArgs(t *testing.T) {
+	tmpl, err := New("").Option("escape=contextual").Parse(`SELECT * FROM t WHERE a = {{.}} AND b LIKE '{{.}}%'`)
+	qt.Assert(t, err, qt.IsNil)
+
+	query, args, err := tmpl.ExecuteArgs("it's")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `SELECT * FROM t WHERE a = $1 AND b LIKE 'it''s%'`)
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"it's"})
+}
+
+func TestEscapeErrorIsSticky(t *testing.T) {
+	tmpl := New("").Option("escape=contextual")
+	_, err := tmpl.Parse(`SELECT 1 -- {{.}}`)
+	qt.Assert(t, err, qt.ErrorMatches, `sqltemplate: :1:14: {{.}} appears inside a line comment`)
+
+	err = tmpl.Execute(new(strings.Builder), nil)
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate: :1:14: {{.}} appears inside a line comment`)
+
+	_, _, err = tmpl.ExecuteArgs(nil)
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate: :1:14: {{.}} appears inside a line comment`)
+}
+
+func TestAddParseTreeEscapeErrorIsNotSticky(t *testing.T) {
+	tmpl, err := New("").Parse(`SELECT {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	tt, err := template.New("bad").Parse(`SELECT 1 -- {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	_, err = tmpl.AddParseTree("bad", tt.Tree)
+	qt.Assert(t, err, qt.ErrorMatches, `sqltemplate: bad:1:14: {{.}} appears inside a line comment`)
+	qt.Check(t, tmpl.Lookup("bad"), qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, 1)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT 1`)
+}
+
+func TestContextualEscapingIdempotent(t *testing.T) {
+	tmpl, err := New("a").Option("escape=contextual").Parse(`SELECT '{{.}}'`)
+	qt.Assert(t, err, qt.IsNil)
+	_, err = tmpl.New("b").Parse(`SELECT "{{.}}"`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, "it's")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT 'it''s'`)
+}
+
+var unsafePositionTests = []struct {
+	name        string
+	dialect     Dialect
+	text        string
+	expectError string
+}{{
+	name:        "string",
+	text:        `SELECT * FROM t WHERE name = '{{.}}'`,
+	expectError: `sqltemplate: :1:32: {{.}} appears inside a string literal`,
+}, {
+	name:        "identifier",
+	text:        `SELECT * FROM "{{.}}"`,
+	expectError: `sqltemplate: :1:17: {{.}} appears inside a quoted identifier`,
+}, {
+	name:        "dollar quote",
+	text:        `SELECT $fn${{.}}$fn$`,
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a dollar-quoted string`,
+}, {
+	name:        "line comment",
+	text:        "SELECT 1\n-- {{.}}\n",
+	expectError: `sqltemplate: :2:5: {{.}} appears inside a line comment`,
+}, {
+	name:        "block comment",
+	text:        "SELECT 1 /*\n {{.}} */",
+	expectError: `sqltemplate: :2:3: {{.}} appears inside a block comment`,
+}, {
+	name:        "mysql string",
+	dialect:     MySQL{},
+	text:        `SELECT "{{.}}"`,
+	expectError: `sqltemplate: :1:10: {{.}} appears inside a string literal`,
+}, {
+	name:        "mysql hash comment",
+	dialect:     MySQL{},
+	text:        "SELECT 1 # {{.}}\n",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}, {
+	name:        "break inside string",
+	text:        `SELECT {{range .L}}'a{{if .}}{{break}}{{end}}b'{{end}} = {{.Y}}`,
+	expectError: `sqltemplate: :1:31: {{break}} appears inside a string literal but the loop body starts in SQL code`,
+}, {
+	name:        "nested",
+	text:        `{{if .}}{{range .}}SELECT '{{.}}'{{end}}{{end}}`,
+	expectError: `sqltemplate: :1:29: {{.}} appears inside a string literal`,
+}, {
+	name:        "defined template",
+	text:        `{{define "a"}}SELECT 1 -- {{.}}{{end}}`,
+	expectError: `sqltemplate: :1:28: {{.}} appears inside a line comment in template "a"`,
+}, {
+	name:        "unterminated string",
+	text:        `SELECT 'a`,
+	expectError: `sqltemplate: : template ends inside a string literal`,
+}, {
+	name:        "minus split by branch",
+	text:        `SELECT 1-{{if .}}{{end}}-{{.}}`,
+	expectError: `sqltemplate: :1:27: {{.}} appears inside a line comment`,
+}, {
+	name:        "minus before template",
+	text:        `{{define "a"}}-1{{end}}SELECT 1-{{template "a"}}`,
+	expectError: `sqltemplate: :1:43: {{template "a"}} appears after -, which might start a comment`,
+}, {
+	name:        "call template ending in minus",
+	text:        `{{define "a"}}SELECT 1 -{{end}}{{template "a"}}{{.}} AND tenant = 7`,
+	expectError: `sqltemplate: :1:42: {{template "a"}} calls a template that ends in SQL code after -`,
+}, {
+	name:        "mysql call template ending in dashes",
+	dialect:     MySQL{},
+	text:        `{{define "a"}}SELECT 1 --{{end}}{{template "a"}} AND tenant = 7`,
+	expectError: `sqltemplate: :1:43: {{template "a"}} calls a template that ends in SQL code after --`,
+}, {
+	name:        "call template ending in comment",
+	text:        `{{define "a"}}SELECT 1 -- a{{end}}{{template "a"}} AND {{.}}`,
+	expectError: `sqltemplate: :1:45: {{template "a"}} calls a template that ends inside a line comment`,
+}}
+
+func TestUnsafePositions(t *testing.T) {
+	for _, test := range unsafePositionTests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl := New("")
+			if test.dialect != nil {
+				tmpl.WithDialect(test.dialect)
+			}
+			_, err := tmpl.Parse(test.text)
+			qt.Check(t, err, qt.ErrorMatches, regexp.QuoteMeta(test.expectError))
+		})
+	}
+}
+
+func TestSafePositions(t *testing.T) {
+	tmpl, err := New("").Parse(`SELECT 'it''s', "a""b" -- it's
+/* it's */ FROM t WHERE a = {{.}} AND b = $$it's$$`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, "x")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT 'it''s', "a""b" -- it's
+/* it's */ FROM t WHERE a = 'x' AND b = $$it's$$`)
+}
+
+func TestTrailingLineComment(t *testing.T) {
+	tmpl, err := New("").Parse(`SELECT {{.}} -- trailing comment`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, "x")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT 'x' -- trailing comment`)
+
+	// Calling the template would put the caller's text in the comment,
+	// whichever order the templates are parsed in.
+	_, err = tmpl.New("b").Parse(`{{template ""}} AND {{.}}`)
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate: b:1:11: {{template ""}} calls a template that ends inside a line comment`)
+
+	tmpl, err = New("a").Parse(`{{template "b"}} AND {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	_, err = tmpl.New("b").Parse(`SELECT 1 -- b`)
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate: a:1:11: {{template "b"}} calls a template that ends inside a line comment`)
+}
+
+// A wrappedDialect wraps another dialect, so its syntax can only be
+// determined using the Dialect methods.
+type wrappedDialect struct {
+	Dialect
+}
+
+var dialectPositionTests = []struct {
+	name        string
+	dialect     Dialect
+	text        string
+	expectError string
+}{{
+	name:    "postgres nested comment",
+	dialect: Postgres{},
+	text:    `SELECT 1 /* /* */ */ {{.}}`,
+}, {
+	name:        "postgres inside nested comment",
+	dialect:     Postgres{},
+	text:        `SELECT 1 /* /* */ {{.}} */`,
+	expectError: `sqltemplate: :1:20: {{.}} appears inside a block comment`,
+}, {
+	name:    "mysql dash without space",
+	dialect: MySQL{},
+	text:    `SELECT 1--{{.}}`,
+}, {
+	name:    "mysql dashes in expression",
+	dialect: MySQL{},
+	text:    `SELECT 1--1, {{.}}`,
+}, {
+	name:        "mysql dash comment",
+	dialect:     MySQL{},
+	text:        "SELECT 1--\t{{.}}",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}, {
+	name:        "mysql dash before template",
+	dialect:     MySQL{},
+	text:        `{{define "a"}} a{{end}}SELECT 1--{{template "a"}}`,
+	expectError: `sqltemplate: :1:44: {{template "a"}} appears after --, which might start a comment`,
+}, {
+	// The comment starts after the {{if}} action, so the quote is
+	// inside it.
+	name:    "mysql dash comment split by action",
+	dialect: MySQL{},
+	text:    "SELECT 1--{{if .}}{{end}} '\n, {{.}}",
+}, {
+	name:        "mysql dash split by branch",
+	dialect:     MySQL{},
+	text:        "SELECT 1--{{if .}} {{end}}{{.}}",
+	expectError: `sqltemplate: :1:15: branches end in different contexts: a line comment, SQL code after --`,
+}, {
+	name:        "mysql hash comment",
+	dialect:     MySQL{},
+	text:        "SELECT 1 # {{.}}\n",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}, {
+	name:        "clickhouse escaped backtick",
+	dialect:     ClickHouse{},
+	text:        "SELECT * FROM `t_\\` WHERE x = {{.}}",
+	expectError: `sqltemplate: :1:32: {{.}} appears inside a quoted identifier`,
+}, {
+	name:        "sqlite bracket identifier",
+	dialect:     SQLite{},
+	text:        `SELECT [{{.}}] FROM t`,
+	expectError: `sqltemplate: :1:10: {{.}} appears inside a quoted identifier`,
+}, {
+	name:    "sqlite brackets cannot be escaped",
+	dialect: SQLite{},
+	text:    `SELECT [a]]{{.}}`,
+}, {
+	name:        "sqlite backtick identifier",
+	dialect:     SQLite{},
+	text:        "SELECT `{{.}}` FROM t",
+	expectError: `sqltemplate: :1:10: {{.}} appears inside a quoted identifier`,
+}, {
+	name:        "mysql ansi quotes backtick identifier",
+	dialect:     MySQL{ANSIQuotes: true},
+	text:        "SELECT `{{.}}` FROM t",
+	expectError: `sqltemplate: :1:10: {{.}} appears inside a quoted identifier`,
+}, {
+	name:    "sqlite block comments do not nest",
+	dialect: SQLite{},
+	text:    `SELECT 1 /* /* */ {{.}}`,
+}, {
+	name:        "sqlite line comment",
+	dialect:     SQLite{},
+	text:        "SELECT 1 --{{.}}",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}, {
+	name:    "sqlserver nested comment",
+	dialect: SQLServer{},
+	text:    `SELECT 1 /* /* */ */ {{.}}`,
+}, {
+	name:        "sqlserver inside nested comment",
+	dialect:     SQLServer{},
+	text:        `SELECT 1 /* /* */ {{.}} */`,
+	expectError: `sqltemplate: :1:20: {{.}} appears inside a block comment`,
+}, {
+	name:    "oracle q quote",
+	dialect: Oracle{},
+	text:    `SELECT q'[it's]' || nq'{a}' || Q'!b!' || {{.}}`,
+}, {
+	name:        "oracle inside q quote",
+	dialect:     Oracle{},
+	text:        `SELECT q'[it's {{.}}]'`,
+	expectError: `sqltemplate: :1:17: {{.}} appears inside a string literal with custom delimiters`,
+}, {
+	name:        "oracle q quote delimiter",
+	dialect:     Oracle{},
+	text:        `SELECT q'{{.}}`,
+	expectError: `sqltemplate: :1:11: {{.}} appears inside a string literal with custom delimiters`,
+}, {
+	name:    "clickhouse hash in string",
+	dialect: ClickHouse{},
+	text:    `SELECT 'a#' || {{.}}`,
+}, {
+	name:        "clickhouse hash comment",
+	dialect:     ClickHouse{},
+	text:        "SELECT 1 # {{.}}\n",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}, {
+	name:    "duckdb nested comment",
+	dialect: DuckDB{},
+	text:    `SELECT 1 /* /* */ */ {{.}}`,
+}, {
+	name:        "duckdb escape string",
+	dialect:     DuckDB{},
+	text:        `SELECT E'a\' {{.}}'`,
+	expectError: `sqltemplate: :1:15: {{.}} appears inside a string literal`,
+}, {
+	name:    "bigquery triple quote",
+	dialect: BigQuery{},
+	text:    `SELECT '''it's''' || """a "b" c""" || r'\d' || {{.}}`,
+}, {
+	name:        "bigquery inside triple quote",
+	dialect:     BigQuery{},
+	text:        `SELECT """a {{.}}"""`,
+	expectError: `sqltemplate: :1:14: {{.}} appears inside a string literal with custom delimiters`,
+}, {
+	name:        "bigquery hash comment",
+	dialect:     BigQuery{},
+	text:        "SELECT 1 # {{.}}\n",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}, {
+	name:    "wrapped dialect",
+	dialect: wrappedDialect{MySQL{}},
+	text:    `SELECT 1--{{.}}`,
+}, {
+	name:        "wrapped dialect hash comment",
+	dialect:     wrappedDialect{MySQL{}},
+	text:        "SELECT 1 # {{.}}\n",
+	expectError: `sqltemplate: :1:13: {{.}} appears inside a line comment`,
+}}
+
+func TestDialectPositions(t *testing.T) {
+	for _, test := range dialectPositionTests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New("").WithDialect(test.dialect).Parse(test.text)
+			if test.expectError == "" {
+				qt.Check(t, err, qt.IsNil)
+				return
+			}
+			qt.Check(t, err, qt.ErrorMatches, regexp.QuoteMeta(test.expectError))
+		})
+	}
+}
+
+func TestUnsafePositionsParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.tmpl": &fstest.MapFile{Data: []byte("SELECT 1\n-- {{.}}\n")},
+	}
+	_, err := ParseFS(fsys, "*.tmpl")
+	qt.Check(t, err, qt.ErrorMatches, regexp.QuoteMeta(`sqltemplate: a.tmpl:2:5: {{.}} appears inside a line comment`))
+}
+
+func TestPendingEscapeAcrossComment(t *testing.T) {
+	_, err := New("").Parse(`SELECT E'a\{{/* c */}}' AND tenant = 1`)
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate: : template ends inside a string literal`)
+}
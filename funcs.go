@@ -0,0 +1,194 @@
+package sqltemplate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// emptyInMode determines how the in function handles empty lists.
+type emptyInMode int
+
+const (
+	emptyInError emptyInMode = iota
+	emptyInNull
+)
+
+// in implements the in template function. The elements of the slice or
+// array v are formatted using literal and returned as a parenthesized,
+// comma-separated list.
+func (ns *nameSpace) in(literal func(interface{}) (RawSQL, error), v interface{}) (RawSQL, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return "", fmt.Errorf("cannot expand %T as a list", v)
+	}
+	if ns.denyRawSQL && containsRawSQL(rv) {
+		return "", errors.New("RawSQL values are not allowed in template data")
+	}
+	if rv.Len() == 0 {
+		if ns.emptyIn == emptyInNull {
+			return RawSQL("(NULL)"), nil
+		}
+		return "", errors.New("empty list")
+	}
+	var sb strings.Builder
+	sb.WriteString("(")
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		s, err := literal(rv.Index(i).Interface())
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(s))
+	}
+	sb.WriteString(")")
+	return RawSQL(sb.String()), nil
+}
+
+// jsonFunc implements the json template function.
+func jsonFunc(v interface{}) JSON {
+	return JSON{Data: v}
+}
+
+var (
+	rawSQLType = reflect.TypeOf(RawSQL(""))
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// untrustedFunc wraps the template function fn, which was added with
+// Funcs, so that it returns an error if it produces a RawSQL value. Only
+// functions registered with TrustedFuncs may produce SQL. Functions that
+// cannot return RawSQL values, or that are not valid template functions,
+// are returned unchanged.
+func untrustedFunc(name string, fn interface{}) interface{} {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumOut() < 1 || ft.NumOut() > 2 {
+		return fn
+	}
+	out := ft.Out(0)
+	if out != rawSQLType && (out.Kind() != reflect.Interface || !rawSQLType.Implements(out)) {
+		return fn
+	}
+	if ft.NumOut() == 2 && ft.Out(1) != errorType {
+		return fn
+	}
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	wt := reflect.FuncOf(in, []reflect.Type{out, errorType}, ft.IsVariadic())
+	return reflect.MakeFunc(wt, func(args []reflect.Value) []reflect.Value {
+		var results []reflect.Value
+		if ft.IsVariadic() {
+			results = fv.CallSlice(args)
+		} else {
+			results = fv.Call(args)
+		}
+		errv := reflect.Zero(errorType)
+		if len(results) == 2 {
+			errv = results[1]
+		}
+		v := results[0]
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			v = v.Elem()
+		}
+		if errv.IsNil() && v.Type() == rawSQLType {
+			err := fmt.Errorf("function %q returned RawSQL, functions that produce SQL must be added with TrustedFuncs", name)
+			errv = reflect.ValueOf(&err).Elem()
+		}
+		if !errv.IsNil() {
+			return []reflect.Value{reflect.Zero(out), errv}
+		}
+		return []reflect.Value{results[0], errv}
+	}).Interface()
+}
+
+// checkRawSQL implements the function added to pipelines that format
+// template data when RawSQL values are denied. It returns v unchanged
+// unless v contains a RawSQL value.
+func checkRawSQL(v interface{}) (interface{}, error) {
+	if containsRawSQL(reflect.ValueOf(v)) {
+		return nil, errors.New("RawSQL values are not allowed in template data")
+	}
+	return v, nil
+}
+
+// containsRawSQL reports whether rv is, or contains, a RawSQL value.
+// Values contained in pointers, interfaces, slices, arrays, maps and the
+// exported fields of structs are checked. JSON values are not checked as
+// they are always encoded as a JSON document.
+func containsRawSQL(rv reflect.Value) bool {
+	return rawSQLVisitor{}.contains(rv)
+}
+
+// rawSQLVisitor records the pointers, slices and maps that have already
+// been checked, so that values containing cycles can be checked.
+type rawSQLVisitor map[rawSQLRef]bool
+
+// A rawSQLRef identifies a value that refers to other values.
+type rawSQLRef struct {
+	ptr uintptr
+	len int
+	t   reflect.Type
+}
+
+func (c rawSQLVisitor) contains(rv reflect.Value) bool {
+	if !rv.IsValid() {
+		return false
+	}
+	switch t := rv.Type(); {
+	case t == rawSQLType:
+		return true
+	case t == reflect.TypeOf(JSON{}):
+		return false
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		if rv.IsNil() {
+			return false
+		}
+		ref := rawSQLRef{ptr: rv.Pointer(), t: rv.Type()}
+		if rv.Kind() == reflect.Slice {
+			ref.len = rv.Len()
+		}
+		if c[ref] {
+			return false
+		}
+		c[ref] = true
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !rv.IsNil() && c.contains(rv.Elem())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if c.contains(rv.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			if c.contains(iter.Key()) || c.contains(iter.Value()) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" && c.contains(rv.Field(i)) {
+				return true
+			}
+		}
+	}
+	return false
+}
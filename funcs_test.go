@@ -0,0 +1,371 @@
+package sqltemplate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+var inTests = []struct {
+	name        string
+	options     []string
+	value       interface{}
+	expectSQL   string
+	expectError string
+}{{
+	name:      "ints",
+	value:     []int{1, 2, 3},
+	expectSQL: `a IN (1, 2, 3)`,
+}, {
+	name:      "strings",
+	value:     []string{"a", "b'c"},
+	expectSQL: `a IN ('a', 'b''c')`,
+}, {
+	name:      "array",
+	value:     [2]interface{}{Identifier("b"), nil},
+	expectSQL: `a IN ("b", NULL)`,
+}, {
+	name:      "slice pointer",
+	value:     &[]int64{1},
+	expectSQL: `a IN (1)`,
+}, {
+	name:        "empty",
+	value:       []int{},
+	expectError: `template: :1:7: executing "" at <in .>: error calling in: empty list`,
+}, {
+	name:        "empty error",
+	options:     []string{"emptyin=error"},
+	value:       []int{},
+	expectError: `template: :1:7: executing "" at <in .>: error calling in: empty list`,
+}, {
+	name:      "empty null",
+	options:   []string{"emptyin=null"},
+	value:     []int{},
+	expectSQL: `a IN (NULL)`,
+}, {
+	name:        "not a list",
+	value:       1,
+	expectError: `template: :1:7: executing "" at <in .>: error calling in: cannot expand int as a list`,
+}, {
+	name:        "invalid element",
+	value:       []interface{}{make(chan bool)},
+	expectError: `template: :1:7: executing "" at <in .>: error calling in: unknown type chan bool`,
+}}
+
+func TestIn(t *testing.T) {
+	for _, test := range inTests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := New("").Option(test.options...).Parse(`a IN {{in .}}`)
+			qt.Assert(t, err, qt.IsNil)
+
+			var sb strings.Builder
+			err = tmpl.Execute(&sb, test.value)
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, sb.String(), qt.Equals, test.expectSQL)
+		})
+	}
+}
+
+func TestInExecuteArgs(t *testing.T) {
+	tmpl, err := New("").Parse(`a IN {{in .A}} AND b = {{.B}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	query, args, err := tmpl.ExecuteArgs(map[string]interface{}{
+		"A": []interface{}{1, Identifier("c"), "x"},
+		"B": "y",
+	})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `a IN ($1, "c", $2) AND b = $3`)
+	qt.Check(t, args, qt.DeepEquals, []interface{}{1, "x", "y"})
+}
+
+func TestInUsesSQLLiteral(t *testing.T) {
+	tmpl, err := New("").Funcs(FuncMap{
+		"sqlliteral": func(v interface{}) (RawSQL, error) {
+			if s, ok := v.(RawSQL); ok {
+				return s, nil
+			}
+			return RawSQL("<x>"), nil
+		},
+	}).Parse(`{{in .}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, []int{1, 2})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `(<x>, <x>)`)
+
+	tmpl, err = New("").WithDialect(MySQL{}).Parse(`{{in .}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	sb.Reset()
+	err = tmpl.Execute(&sb, []Identifier{"a", "b"})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, "(`a`, `b`)")
+}
+
+func TestInOverridden(t *testing.T) {
+	tmpl, err := New("").Funcs(FuncMap{
+		"in": func(v interface{}) string {
+			return "overridden"
+		},
+	}).Parse(`{{in .}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	tmpl, err = tmpl.Clone()
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, []int{1, 2})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `'overridden'`)
+
+	query, _, err := tmpl.ExecuteArgs([]int{1, 2})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `$1`)
+}
+
+func TestInClone(t *testing.T) {
+	t1, err := New("").Option("emptyin=null").Parse(`{{in .}}`)
+	qt.Assert(t, err, qt.IsNil)
+	t2, err := t1.Clone()
+	qt.Assert(t, err, qt.IsNil)
+	t2.Option("emptyin=error")
+
+	var sb strings.Builder
+	err = t1.Execute(&sb, []int{})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `(NULL)`)
+
+	err = t2.Execute(&sb, []int{})
+	qt.Check(t, err, qt.ErrorMatches, `.*empty list`)
+}
+
+func TestJSON(t *testing.T) {
+	tmpl, err := New("").Parse(`INSERT INTO t VALUES ({{json .}})`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, map[string]string{"a": "b"})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `INSERT INTO t VALUES ('{"a":"b"}'::jsonb)`)
+
+	query, args, err := tmpl.ExecuteArgs(map[string]string{"a": "b"})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `INSERT INTO t VALUES ($1)`)
+	qt.Check(t, args, qt.DeepEquals, []interface{}{JSON{Data: map[string]string{"a": "b"}}})
+
+	tmpl, err = New("").WithDialect(MySQL{}).Parse(`INSERT INTO t VALUES ({{json .}})`)
+	qt.Assert(t, err, qt.IsNil)
+
+	sb.Reset()
+	err = tmpl.Execute(&sb, map[string]string{"a": "b"})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `INSERT INTO t VALUES ('{\"a\":\"b\"}')`)
+}
+
+var trustTests = []struct {
+	name        string
+	options     []string
+	text        string
+	data        interface{}
+	expectSQL   string
+	expectError string
+}{{
+	name:        "untrusted function",
+	text:        `SELECT {{untrusted .}}`,
+	data:        "a",
+	expectError: `template: :1:9: executing "" at <untrusted .>: error calling untrusted: function "untrusted" returned RawSQL, functions that produce SQL must be added with TrustedFuncs`,
+}, {
+	name:        "untrusted interface function",
+	text:        `SELECT {{untrustediface .}}`,
+	data:        "a",
+	expectError: `.*function "untrustediface" returned RawSQL, .*`,
+}, {
+	name:      "untrusted interface function non-raw",
+	text:      `SELECT {{untrustediface 1}}`,
+	expectSQL: `SELECT 1`,
+}, {
+	name:        "untrusted variadic function",
+	text:        `SELECT {{untrustedvariadic "a" "b"}}`,
+	expectError: `.*function "untrustedvariadic" returned RawSQL, .*`,
+}, {
+	name:        "untrusted function error",
+	text:        `SELECT {{untrustederror}}`,
+	expectError: `.*error calling untrustederror: test error`,
+}, {
+	name:      "trusted function",
+	text:      `SELECT {{trusted .}}`,
+	data:      "a",
+	expectSQL: `SELECT a`,
+}, {
+	name:      "raw data allowed",
+	text:      `SELECT {{.}}`,
+	data:      RawSQL("a"),
+	expectSQL: `SELECT a`,
+}, {
+	name:        "raw data denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        RawSQL("a"),
+	expectError: `template: :1:9: executing "" at <_sqltemplate_rawsqlchecker>: error calling _sqltemplate_rawsqlchecker: RawSQL values are not allowed in template data`,
+}, {
+	name:        "nested raw data denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        Array{Elems: []interface{}{"a", map[string]interface{}{"b": RawSQL("c")}}},
+	expectError: `.*RawSQL values are not allowed in template data`,
+}, {
+	name:        "raw data in denied",
+	options:     []string{"rawsql=deny"},
+	text:        `a IN {{in .}}`,
+	data:        []interface{}{1, RawSQL("b")},
+	expectError: `.*error calling in: RawSQL values are not allowed in template data`,
+}, {
+	name:      "data denied",
+	options:   []string{"rawsql=deny"},
+	text:      `SELECT {{.}} FROM t WHERE a IN {{in .}} {{sqlliteral nil}}`,
+	data:      []interface{}{1, Identifier("b"), []byte("c")},
+	expectSQL: `SELECT ARRAY[1, "b", '\x63'] FROM t WHERE a IN (1, "b", '\x63') NULL`,
+}, {
+	name:        "explicit sqlliteral denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{sqlliteral .}}`,
+	data:        RawSQL("a"),
+	expectError: `.*RawSQL values are not allowed in template data`,
+}, {
+	name:      "explicit sqlliteral",
+	options:   []string{"rawsql=deny"},
+	text:      `SELECT {{sqlliteral .}}, {{. | sqlliteral}}`,
+	data:      "a",
+	expectSQL: `SELECT 'a', 'a'`,
+}, {
+	name:      "trusted function denied",
+	options:   []string{"rawsql=deny"},
+	text:      `SELECT {{trusted .}}, {{json .}}`,
+	data:      "a",
+	expectSQL: `SELECT a, '"a"'::jsonb`,
+}, {
+	name:      "untrusted pipeline denied",
+	options:   []string{"rawsql=deny"},
+	text:      `SELECT {{trusted . | print}}`,
+	data:      "a",
+	expectSQL: `SELECT 'a'`,
+}, {
+	name:        "array element type denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        Array{Elems: []int{1}, ElemType: "int[]; DROP TABLE users; --"},
+	expectError: `.*invalid SQL type name "int\[\]; DROP TABLE users; --"`,
+}, {
+	name:        "range type denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        Range[int]{Empty: true, Type: "int4range; DROP TABLE users; --"},
+	expectError: `.*invalid SQL type name "int4range; DROP TABLE users; --"`,
+}, {
+	name:        "multirange type denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        Multirange[int]{Type: "int4multirange(); DROP TABLE users; --"},
+	expectError: `.*invalid SQL type name "int4multirange\(\); DROP TABLE users; --"`,
+}, {
+	name:        "row type denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        Row{Struct: testItem{}, Type: "item; DROP TABLE users; --"},
+	expectError: `.*invalid SQL type name "item; DROP TABLE users; --"`,
+}, {
+	name:      "type names allowed",
+	options:   []string{"rawsql=deny"},
+	text:      `SELECT {{.A}}, {{.R}}`,
+	data:      map[string]interface{}{"A": Array{Elems: []int{1}, ElemType: "integer"}, "R": Row{Struct: testItem{}, Type: "public.item"}},
+	expectSQL: `SELECT ARRAY[1]::integer[], ROW('', 0, 0)::public.item`,
+}, {
+	name:        "cyclic data denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        cyclicData(1),
+	expectError: `.*the value might contain a cycle`,
+}, {
+	name:        "cyclic raw data denied",
+	options:     []string{"rawsql=deny"},
+	text:        `SELECT {{.}}`,
+	data:        cyclicData(RawSQL("a")),
+	expectError: `.*RawSQL values are not allowed in template data`,
+}}
+
+// cyclicData returns a slice that contains itself followed by v.
+func cyclicData(v interface{}) []interface{} {
+	s := []interface{}{nil, v}
+	s[0] = s
+	return s
+}
+
+func TestTrustedFuncs(t *testing.T) {
+	for _, test := range trustTests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := New("").Option(test.options...).Funcs(FuncMap{
+				"untrusted": func(s string) RawSQL {
+					return RawSQL(s)
+				},
+				"untrustediface": func(v interface{}) (interface{}, error) {
+					if s, ok := v.(string); ok {
+						return RawSQL(s), nil
+					}
+					return v, nil
+				},
+				"untrustedvariadic": func(s ...string) RawSQL {
+					return RawSQL(strings.Join(s, ", "))
+				},
+				"untrustederror": func() (RawSQL, error) {
+					return "", errors.New("test error")
+				},
+			}).TrustedFuncs(FuncMap{
+				"trusted": func(s string) RawSQL {
+					return RawSQL(s)
+				},
+			}).Parse(test.text)
+			qt.Assert(t, err, qt.IsNil)
+
+			var sb strings.Builder
+			err = tmpl.Execute(&sb, test.data)
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, sb.String(), qt.Equals, test.expectSQL)
+		})
+	}
+}
+
+func TestTrustedFuncsReplaced(t *testing.T) {
+	f := func() RawSQL { return "a" }
+	tmpl, err := New("").TrustedFuncs(FuncMap{"f": f}).Funcs(FuncMap{"f": f}).Parse(`{{f}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, nil)
+	qt.Check(t, err, qt.ErrorMatches, `.*function "f" returned RawSQL, .*`)
+
+	tmpl, err = New("").Option("rawsql=deny").Funcs(FuncMap{"json": f}).Parse(`{{json}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	err = tmpl.Execute(&sb, nil)
+	qt.Check(t, err, qt.ErrorMatches, `.*function "json" returned RawSQL, .*`)
+
+	tmpl, err = New("").Option("rawsql=deny").Funcs(FuncMap{"json": f}).TrustedFuncs(FuncMap{"json": f}).Parse(`{{json}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	sb.Reset()
+	err = tmpl.Execute(&sb, nil)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `a`)
+}
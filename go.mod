@@ -1,8 +1,14 @@
 module github.com/mhilton/sqltemplate
 
-go 1.16
+go 1.18
 
 require (
 	github.com/frankban/quicktest v1.14.6
 	github.com/google/go-cmp v0.6.0
 )
+
+require (
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/rogpeppe/go-internal v1.9.0 // indirect
+)
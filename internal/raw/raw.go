@@ -0,0 +1,8 @@
+// Package raw provides a means for the unsafesql package to create values
+// of the types defined in the sqltemplate package without exporting
+// unsafe constructors from sqltemplate itself.
+package raw
+
+// TrustedSQL is set by the sqltemplate package to a function of type
+// func(string) sqltemplate.TrustedSQL.
+var TrustedSQL interface{}
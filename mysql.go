@@ -0,0 +1,174 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MySQL is the Dialect for the MySQL and MariaDB databases. The fields of
+// MySQL must match the sql_mode of the session the query will be run in.
+type MySQL struct {
+	// NoBackslashEscapes must be set if the NO_BACKSLASH_ESCAPES mode
+	// is enabled.
+	NoBackslashEscapes bool
+
+	// ANSIQuotes must be set if the ANSI_QUOTES mode is enabled.
+	ANSIQuotes bool
+
+	// Location is the time zone time.Time values are converted to
+	// before formatting. If this is nil then UTC is used.
+	Location *time.Location
+}
+
+// Name implements Dialect.
+func (MySQL) Name() string {
+	return "mysql"
+}
+
+// QuoteIdentifier implements Dialect. Identifiers are quoted using
+// backticks, unless ANSIQuotes is set in which case double quotes are
+// used.
+func (d MySQL) QuoteIdentifier(name string) (RawSQL, error) {
+	q := "`"
+	if d.ANSIQuotes {
+		q = `"`
+	}
+	return RawSQL(q + strings.ReplaceAll(name, q, q+q) + q), nil
+}
+
+// Bool implements Dialect.
+func (MySQL) Bool(b bool) (RawSQL, error) {
+	if b {
+		return RawSQL("TRUE"), nil
+	}
+	return RawSQL("FALSE"), nil
+}
+
+// Null implements Dialect.
+func (MySQL) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, MySQL drivers use
+// QuestionPlaceholders.
+func (MySQL) Placeholders() PlaceholderStyle {
+	return QuestionPlaceholders
+}
+
+// Supports implements Dialect. MySQL supports FeatureBooleans,
+// FeatureHashComments, FeatureDashCommentSpace,
+// FeatureBacktickIdentifiers, and FeatureBackslashEscapes unless
+// NoBackslashEscapes is set.
+func (d MySQL) Supports(f Feature) bool {
+	switch f {
+	case FeatureBooleans, FeatureHashComments, FeatureDashCommentSpace, FeatureBacktickIdentifiers:
+		return true
+	case FeatureBackslashEscapes:
+		return !d.NoBackslashEscapes
+	}
+	return false
+}
+
+// MySQLLiteral formats the value v as a literal suitable for use in
+// queries used with the MySQL or MariaDB databases, in a session with the
+// default sql_mode. It is equivalent to calling the Literal method of a
+// zero MySQL value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  Either the SQL keyword TRUE, or FALSE.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  The %g encoding provided by fmt.Printf. MySQL cannot represent
+//	  +Inf, -Inf or NaN so these values result in an error.
+//	string
+//	  A string literal. Unless NoBackslashEscapes is set, special
+//	  characters are escaped using backslashes, see
+//	  https://dev.mysql.com/doc/refman/8.0/en/string-literals.html.
+//	[]byte
+//	  A hexadecimal literal, see
+//	  https://dev.mysql.com/doc/refman/8.0/en/hexadecimal-literals.html.
+//	time.Time
+//	  A string literal containing the date and time, in Location, with
+//	  microsecond precision.
+//	Identifier
+//	  A quoted identifier, see
+//	  https://dev.mysql.com/doc/refman/8.0/en/identifiers.html.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func MySQLLiteral(v interface{}) (RawSQL, error) {
+	return MySQL{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// MySQLLiteral.
+func (d MySQL) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if v1 == nil {
+			return d.Null(), nil
+		}
+		return RawSQL(fmt.Sprintf("X'%X'", v1)), nil
+	case float64:
+		if math.IsInf(v1, 0) || math.IsNaN(v1) {
+			return "", fmt.Errorf("cannot represent %v in MySQL", v1)
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		return d.quoteString(v1), nil
+	case time.Time:
+		loc := d.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		return RawSQL(`'` + v1.In(loc).Format("2006-01-02 15:04:05.999999") + `'`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
+
+// mysqlEscaper escapes the special characters in a string literal when
+// backslash escapes are enabled.
+var mysqlEscaper = strings.NewReplacer(
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+	`'`, `\'`,
+	`"`, `\"`,
+	`\`, `\\`,
+)
+
+func (d MySQL) quoteString(s string) RawSQL {
+	if d.NoBackslashEscapes {
+		return RawSQL(`'` + strings.ReplaceAll(s, `'`, `''`) + `'`)
+	}
+	return RawSQL(`'` + mysqlEscaper.Replace(s) + `'`)
+}
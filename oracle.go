@@ -0,0 +1,205 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Oracle is the Dialect for the Oracle database.
+type Oracle struct {
+	// Booleans determines how bool values are formatted.
+	Booleans OracleBooleans
+}
+
+// OracleBooleans determines how bool values are formatted by the Oracle
+// dialect.
+type OracleBooleans int
+
+const (
+	// OracleBooleansError causes formatting a bool value to fail. Oracle
+	// databases prior to version 23c do not support boolean values in
+	// SQL.
+	OracleBooleansError OracleBooleans = iota
+
+	// OracleBooleansNumber formats bool values as the numbers 1 and 0.
+	OracleBooleansNumber
+
+	// OracleBooleansLiteral formats bool values as the literals TRUE
+	// and FALSE. These are supported from Oracle 23c.
+	OracleBooleansLiteral
+)
+
+// Name implements Dialect.
+func (Oracle) Name() string {
+	return "oracle"
+}
+
+// QuoteIdentifier implements Dialect. Oracle does not allow quoted
+// identifiers to contain double quotes or the NUL character, an error is
+// returned if name contains either of these.
+func (Oracle) QuoteIdentifier(name string) (RawSQL, error) {
+	if strings.ContainsAny(name, "\"\x00") {
+		return "", fmt.Errorf("invalid Oracle identifier %q", name)
+	}
+	return RawSQL(`"` + name + `"`), nil
+}
+
+// Bool implements Dialect, the value is formatted as specified by
+// Booleans.
+func (d Oracle) Bool(b bool) (RawSQL, error) {
+	switch d.Booleans {
+	case OracleBooleansNumber:
+		if b {
+			return RawSQL("1"), nil
+		}
+		return RawSQL("0"), nil
+	case OracleBooleansLiteral:
+		if b {
+			return RawSQL("TRUE"), nil
+		}
+		return RawSQL("FALSE"), nil
+	}
+	return "", fmt.Errorf("cannot represent %v in Oracle", b)
+}
+
+// Null implements Dialect.
+func (Oracle) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, Oracle drivers use ColonPlaceholders.
+func (Oracle) Placeholders() PlaceholderStyle {
+	return ColonPlaceholders
+}
+
+// Supports implements Dialect. Oracle supports FeatureQQuoting, and
+// FeatureBooleans when Booleans is OracleBooleansLiteral.
+func (d Oracle) Supports(f Feature) bool {
+	switch f {
+	case FeatureQQuoting:
+		return true
+	case FeatureBooleans:
+		return d.Booleans == OracleBooleansLiteral
+	}
+	return false
+}
+
+// OracleLiteral formats the value v as a literal suitable for use in
+// queries used with the Oracle database. It is equivalent to calling the
+// Literal method of a zero Oracle value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  As determined by the Booleans field, by default an error.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  If the value represents +Inf, -Inf or NaN then the literal will be
+//	  BINARY_DOUBLE_INFINITY, -BINARY_DOUBLE_INFINITY or
+//	  BINARY_DOUBLE_NAN respectively. Otherwise the %g encoding provided
+//	  by fmt.Printf is used.
+//	string
+//	  A string literal. Strings that contain single quotes use the
+//	  alternative quoting mechanism, for example q'[it's]', where
+//	  possible. Oracle treats empty strings as NULL, so the empty string
+//	  is formatted as NULL.
+//	[]byte
+//	  A call to HEXTORAW with the hexadecimal encoding of the bytes. As
+//	  with strings, an empty value is formatted as NULL.
+//	time.Time
+//	  A TIMESTAMP literal including the time zone offset, for example
+//	  TIMESTAMP '2020-02-02 12:30:45.300001 +00:00'.
+//	Identifier
+//	  A quoted identifier, see
+//	  https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/Database-Object-Names-and-Qualifiers.html.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func OracleLiteral(v interface{}) (RawSQL, error) {
+	return Oracle{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// OracleLiteral.
+func (d Oracle) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if len(v1) == 0 {
+			return d.Null(), nil
+		}
+		return RawSQL(fmt.Sprintf("HEXTORAW('%X')", v1)), nil
+	case float64:
+		switch {
+		case math.IsInf(v1, 1):
+			return RawSQL("BINARY_DOUBLE_INFINITY"), nil
+		case math.IsInf(v1, -1):
+			return RawSQL("-BINARY_DOUBLE_INFINITY"), nil
+		case math.IsNaN(v1):
+			return RawSQL("BINARY_DOUBLE_NAN"), nil
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		if v1 == "" {
+			return d.Null(), nil
+		}
+		return oracleQuoteString(v1), nil
+	case time.Time:
+		return RawSQL(`TIMESTAMP '` + v1.Format("2006-01-02 15:04:05.999999999 -07:00") + `'`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
+
+// oracleQuoteDelimiters are the delimiters tried, in order, when quoting
+// a string using the alternative quoting mechanism.
+var oracleQuoteDelimiters = []struct {
+	open, close string
+}{
+	{"[", "]"},
+	{"{", "}"},
+	{"(", ")"},
+	{"<", ">"},
+	{"!", "!"},
+	{"#", "#"},
+	{"|", "|"},
+	{"~", "~"},
+}
+
+// oracleQuoteString quotes s as an Oracle string literal. If s contains
+// a single quote then the alternative quoting mechanism is used with the
+// first delimiter that does not conflict with the contents of s.
+func oracleQuoteString(s string) RawSQL {
+	if strings.Contains(s, `'`) {
+		for _, d := range oracleQuoteDelimiters {
+			if !strings.Contains(s, d.close+`'`) {
+				return RawSQL(`q'` + d.open + s + d.close + `'`)
+			}
+		}
+	}
+	return RawSQL(`'` + strings.ReplaceAll(s, `'`, `''`) + `'`)
+}
@@ -0,0 +1,241 @@
+package sqltemplate
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+var oracleLiteralTests = []struct {
+	name      string
+	value     interface{}
+	expectSQL RawSQL
+}{{
+	name:      "nil",
+	value:     nil,
+	expectSQL: "NULL",
+}, {
+	name:      "simple string",
+	value:     "test string",
+	expectSQL: "'test string'",
+}, {
+	name:      "simple string pointer",
+	value:     newString("test string"),
+	expectSQL: "'test string'",
+}, {
+	name:      "empty string",
+	value:     "",
+	expectSQL: "NULL",
+}, {
+	name:      "string with quotes",
+	value:     "test 'string'",
+	expectSQL: "q'[test 'string']'",
+}, {
+	name:      "string with quotes pointer",
+	value:     newString("test 'string'"),
+	expectSQL: "q'[test 'string']'",
+}, {
+	name:      "string with quotes and delimiters",
+	value:     "test ']' '}' ')' '>' '!' '#' '|' '~'",
+	expectSQL: "'test '']'' ''}'' '')'' ''>'' ''!'' ''#'' ''|'' ''~'''",
+}, {
+	name:      "string with quotes and some delimiters",
+	value:     "test ']' '}'",
+	expectSQL: "q'(test ']' '}')'",
+}, {
+	name:      "nil string pointer",
+	value:     (*string)(nil),
+	expectSQL: "NULL",
+}, {
+	name:      "raw sql",
+	value:     RawSQL("'; DROP TABLE users;"),
+	expectSQL: "'; DROP TABLE users;",
+}, {
+	name:      "identifier",
+	value:     Identifier("test identifier"),
+	expectSQL: `"test identifier"`,
+}, {
+	name:      "bytes",
+	value:     []byte("test"),
+	expectSQL: `HEXTORAW('74657374')`,
+}, {
+	name:      "empty bytes",
+	value:     []byte{},
+	expectSQL: `NULL`,
+}, {
+	name:      "nil bytes",
+	value:     []byte(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "float",
+	value:     3.141592654,
+	expectSQL: `3.141592654`,
+}, {
+	name:      "float pointer",
+	value:     newFloat(3.141592654),
+	expectSQL: `3.141592654`,
+}, {
+	name:      "float Inf",
+	value:     math.Inf(0),
+	expectSQL: `BINARY_DOUBLE_INFINITY`,
+}, {
+	name:      "float -Inf",
+	value:     math.Inf(-1),
+	expectSQL: `-BINARY_DOUBLE_INFINITY`,
+}, {
+	name:      "float NaN",
+	value:     math.NaN(),
+	expectSQL: `BINARY_DOUBLE_NAN`,
+}, {
+	name:      "nil float pointer",
+	value:     (*float64)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "nil bool pointer",
+	value:     (*bool)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "int",
+	value:     0,
+	expectSQL: `0`,
+}, {
+	name:      "int pointer",
+	value:     newInt(0),
+	expectSQL: `0`,
+}, {
+	name:      "nil int pointer",
+	value:     (*int)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "int64",
+	value:     int64(1e9),
+	expectSQL: `1000000000`,
+}, {
+	name:      "int64 pointer",
+	value:     newInt64(1e9),
+	expectSQL: `1000000000`,
+}, {
+	name:      "nil int64 pointer",
+	value:     (*int64)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "time",
+	value:     time.Date(2020, time.February, 2, 12, 30, 45, 300001000, time.UTC),
+	expectSQL: `TIMESTAMP '2020-02-02 12:30:45.300001 +00:00'`,
+}, {
+	name:      "time pointer",
+	value:     newTime(time.Date(2020, time.February, 2, 12, 30, 45, 300001000, time.UTC)),
+	expectSQL: `TIMESTAMP '2020-02-02 12:30:45.300001 +00:00'`,
+}, {
+	name:      "niltime pointer",
+	value:     (*time.Time)(nil),
+	expectSQL: `NULL`,
+}, {
+	name: "valuer",
+	value: sql.NullTime{
+		Valid: true,
+		Time:  time.Date(2020, time.February, 2, 12, 30, 45, 300005000, time.FixedZone("UTC-3", -3*60*60)),
+	},
+	expectSQL: `TIMESTAMP '2020-02-02 12:30:45.300005 -03:00'`,
+}}
+
+func TestOracleLiteral(t *testing.T) {
+	for _, test := range oracleLiteralTests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := OracleLiteral(test.value)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, test.expectSQL)
+		})
+	}
+}
+
+func TestOracleLiteralInTemplate(t *testing.T) {
+	tmpl, err := New("").WithDialect(Oracle{}).Parse(`{{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	for _, test := range oracleLiteralTests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := new(strings.Builder)
+			err := tmpl.Execute(sb, test.value)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, sb.String(), qt.Equals, string(test.expectSQL))
+		})
+	}
+}
+
+func TestOracleLiteralUnknown(t *testing.T) {
+	_, err := OracleLiteral(make(chan bool))
+	qt.Check(t, err, qt.ErrorMatches, `unknown type chan bool`)
+}
+
+func TestOracleLiteralInvalidIdentifier(t *testing.T) {
+	_, err := OracleLiteral(Identifier(`test "identifier"`))
+	qt.Check(t, err, qt.ErrorMatches, `invalid Oracle identifier "test \\"identifier\\""`)
+}
+
+var oracleBooleansTests = []struct {
+	name        string
+	booleans    OracleBooleans
+	expectTrue  RawSQL
+	expectFalse RawSQL
+	expectError string
+}{{
+	name:        "error",
+	booleans:    OracleBooleansError,
+	expectError: `cannot represent (true|false) in Oracle`,
+}, {
+	name:        "number",
+	booleans:    OracleBooleansNumber,
+	expectTrue:  "1",
+	expectFalse: "0",
+}, {
+	name:        "literal",
+	booleans:    OracleBooleansLiteral,
+	expectTrue:  "TRUE",
+	expectFalse: "FALSE",
+}}
+
+func TestOracleBooleans(t *testing.T) {
+	for _, test := range oracleBooleansTests {
+		t.Run(test.name, func(t *testing.T) {
+			d := Oracle{Booleans: test.booleans}
+			s1, err1 := d.Literal(true)
+			s2, err2 := d.Literal(newBool(false))
+			qt.Check(t, d.Supports(FeatureBooleans), qt.Equals, test.booleans == OracleBooleansLiteral)
+			if test.expectError != "" {
+				qt.Check(t, err1, qt.ErrorMatches, test.expectError)
+				qt.Check(t, err2, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err1, qt.IsNil)
+			qt.Assert(t, err2, qt.IsNil)
+			qt.Check(t, s1, qt.Equals, test.expectTrue)
+			qt.Check(t, s2, qt.Equals, test.expectFalse)
+		})
+	}
+}
+
+func TestOracleDialect(t *testing.T) {
+	var d Dialect = Oracle{}
+	qt.Check(t, d.Name(), qt.Equals, "oracle")
+	qt.Check(t, d.Null(), qt.Equals, RawSQL("NULL"))
+	qt.Check(t, d.Placeholders(), qt.Equals, ColonPlaceholders)
+	qt.Check(t, d.Supports(FeatureBooleans), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureArrays), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureDollarQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashEscapes), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureEscapeStrings), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureHashComments), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureNestedComments), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureDashCommentSpace), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureQQuoting), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureTripleQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashQuotes), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashIdentifiers), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBacktickIdentifiers), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBracketIdentifiers), qt.IsFalse)
+}
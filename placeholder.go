@@ -0,0 +1,94 @@
+package sqltemplate
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+// A PlaceholderStyle determines how arguments are referenced in the
+// queries produced by ExecuteArgs.
+type PlaceholderStyle interface {
+	// Placeholder returns the placeholder used to refer to the nth
+	// argument. The first argument is number 1.
+	Placeholder(n int) string
+
+	// Arg returns the value that should be passed to the database driver
+	// as the nth argument, the value of which is v.
+	Arg(n int, v interface{}) interface{}
+
+	// Reusable reports whether a placeholder may appear in a query more
+	// than once to refer to the same argument.
+	Reusable() bool
+}
+
+var (
+	// DollarPlaceholders refer to arguments as $1, $2, etc. This style is
+	// used by PostgreSQL drivers such as github.com/lib/pq and
+	// github.com/jackc/pgx.
+	DollarPlaceholders PlaceholderStyle = prefixPlaceholders{prefix: "$"}
+
+	// QuestionPlaceholders refer to all arguments as ?. This style is
+	// used by MySQL and SQLite drivers.
+	QuestionPlaceholders PlaceholderStyle = questionPlaceholders{}
+
+	// AtPPlaceholders refer to arguments as @p1, @p2, etc. This style is
+	// used by SQL Server drivers.
+	AtPPlaceholders PlaceholderStyle = prefixPlaceholders{prefix: "@p"}
+
+	// ColonPlaceholders refer to arguments as :p1, :p2, etc. This style
+	// is used by Oracle drivers. Arguments are passed to the driver as
+	// sql.NamedArg values so that the names are bound correctly.
+	ColonPlaceholders PlaceholderStyle = prefixPlaceholders{prefix: ":p", named: true}
+)
+
+// placeholderStyles contains the styles that can be selected with the
+// "placeholder" option.
+var placeholderStyles = map[string]PlaceholderStyle{
+	"dollar":   DollarPlaceholders,
+	"question": QuestionPlaceholders,
+	"atp":      AtPPlaceholders,
+	"colon":    ColonPlaceholders,
+}
+
+// prefixPlaceholders are numbered placeholders that start with a fixed
+// prefix.
+type prefixPlaceholders struct {
+	prefix string
+	named  bool
+}
+
+// Placeholder implements PlaceholderStyle.
+func (p prefixPlaceholders) Placeholder(n int) string {
+	return p.prefix + strconv.Itoa(n)
+}
+
+// Arg implements PlaceholderStyle.
+func (p prefixPlaceholders) Arg(n int, v interface{}) interface{} {
+	if p.named {
+		return sql.Named(p.Placeholder(n)[1:], v)
+	}
+	return v
+}
+
+// Reusable implements PlaceholderStyle.
+func (prefixPlaceholders) Reusable() bool {
+	return true
+}
+
+// questionPlaceholders are positional placeholders.
+type questionPlaceholders struct{}
+
+// Placeholder implements PlaceholderStyle.
+func (questionPlaceholders) Placeholder(int) string {
+	return "?"
+}
+
+// Arg implements PlaceholderStyle.
+func (questionPlaceholders) Arg(_ int, v interface{}) interface{} {
+	return v
+}
+
+// Reusable implements PlaceholderStyle.
+func (questionPlaceholders) Reusable() bool {
+	return false
+}
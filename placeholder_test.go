@@ -0,0 +1,66 @@
+package sqltemplate
+
+import (
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var ignoreNamedArg = cmpopts.IgnoreUnexported(sql.NamedArg{})
+
+var placeholderStyleTests = []struct {
+	name              string
+	style             PlaceholderStyle
+	expectPlaceholder string
+	expectArg         interface{}
+	expectReusable    bool
+}{{
+	name:              "dollar",
+	style:             DollarPlaceholders,
+	expectPlaceholder: "$2",
+	expectArg:         "value",
+	expectReusable:    true,
+}, {
+	name:              "question",
+	style:             QuestionPlaceholders,
+	expectPlaceholder: "?",
+	expectArg:         "value",
+	expectReusable:    false,
+}, {
+	name:              "atp",
+	style:             AtPPlaceholders,
+	expectPlaceholder: "@p2",
+	expectArg:         "value",
+	expectReusable:    true,
+}, {
+	name:              "colon",
+	style:             ColonPlaceholders,
+	expectPlaceholder: ":p2",
+	expectArg:         sql.Named("p2", "value"),
+	expectReusable:    true,
+}}
+
+func TestPlaceholderStyles(t *testing.T) {
+	for _, test := range placeholderStyleTests {
+		t.Run(test.name, func(t *testing.T) {
+			qt.Check(t, test.style.Placeholder(2), qt.Equals, test.expectPlaceholder)
+			qt.Check(t, test.style.Arg(2, "value"), qt.CmpEquals(ignoreNamedArg), test.expectArg)
+			qt.Check(t, test.style.Reusable(), qt.Equals, test.expectReusable)
+		})
+	}
+}
+
+func TestPlaceholderOption(t *testing.T) {
+	for _, test := range placeholderStyleTests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := New("").Option("placeholder=" + test.name).Parse(`{{.}} {{.}}`)
+			qt.Assert(t, err, qt.IsNil)
+			query, args, err := tmpl.ExecuteArgs("value")
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, query, qt.Equals, test.style.Placeholder(1)+" "+test.style.Placeholder(2))
+			qt.Check(t, args, qt.CmpEquals(ignoreNamedArg), []interface{}{test.style.Arg(1, "value"), test.style.Arg(2, "value")})
+		})
+	}
+}
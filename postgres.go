@@ -1,18 +1,107 @@
 package sqltemplate
 
 import (
-	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// Postgres is the Dialect for the PostgreSQL database. It is the default
+// dialect for templates. The session is expected to have
+// standard_conforming_strings enabled, which is the default since
+// PostgreSQL 9.1.
+type Postgres struct {
+	// Registry, if not nil, contains encoders that are used in
+	// preference to the built-in formatting. This includes formatting
+	// elements of arrays and other composite values.
+	Registry *Registry
+
+	// TimeZones determines how time.Time and TimestampTZ values that
+	// are not in UTC are formatted.
+	TimeZones PostgresTimeZones
+
+	// depth is the number of values that contain the value being
+	// formatted.
+	depth int
+}
+
+// postgresMaxDepth is the maximum depth to which values can be nested
+// inside arrays, rows and ranges. It stops values that contain
+// themselves from being formatted forever.
+const postgresMaxDepth = 100
+
+// PostgresTimeZones determines how the Postgres dialect formats time
+// stamps that have a non-zero offset from UTC.
+type PostgresTimeZones int
+
+const (
+	// PostgresTimeZonesKeep formats time stamps with their offset from
+	// UTC.
+	PostgresTimeZonesKeep PostgresTimeZones = iota
+
+	// PostgresTimeZonesUTC converts time stamps to UTC before they are
+	// formatted.
+	PostgresTimeZonesUTC
+
+	// PostgresTimeZonesError causes formatting a time stamp that is not
+	// in UTC to fail.
+	PostgresTimeZonesError
+)
+
+// Name implements Dialect.
+func (Postgres) Name() string {
+	return "postgres"
+}
+
+// QuoteIdentifier implements Dialect, see
+// https://www.postgresql.org/docs/13/sql-syntax-lexical.html#SQL-SYNTAX-IDENTIFIERS.
+func (Postgres) QuoteIdentifier(name string) (RawSQL, error) {
+	return RawSQL(`"` + strings.ReplaceAll(name, `"`, `""`) + `"`), nil
+}
+
+// Bool implements Dialect.
+func (Postgres) Bool(b bool) (RawSQL, error) {
+	return postgresLiteralBool(b), nil
+}
+
+// Null implements Dialect.
+func (Postgres) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, PostgreSQL drivers use
+// DollarPlaceholders.
+func (Postgres) Placeholders() PlaceholderStyle {
+	return DollarPlaceholders
+}
+
+// Supports implements Dialect. PostgreSQL supports FeatureBooleans,
+// FeatureArrays, FeatureDollarQuoting, FeatureEscapeStrings and
+// FeatureNestedComments.
+func (Postgres) Supports(f Feature) bool {
+	switch f {
+	case FeatureBooleans, FeatureArrays, FeatureDollarQuoting, FeatureEscapeStrings, FeatureNestedComments:
+		return true
+	}
+	return false
+}
+
 // PostgresLiteral formats the value v as a literal suitable for use in
-// queries used with the PostgreSQL database.
+// queries used with the PostgreSQL database. It is equivalent to calling
+// the Literal method of a zero Postgres value.
 //
-// If v implements database/sql/driver.Valuer then Value() will be called
-// before further processing.
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing. Pointers are followed, nil pointers are
+// formatted as NULL.
 //
 // The literal form used for values of a specified type is:
 //
@@ -20,40 +109,142 @@ import (
 //	  The SQL keyword NULL.
 //	bool
 //	  Either the SQL keyword TRUE, or FALSE.
-//	int, int64
-//	  The decimal value.
-//	float64
+//	int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr
+//	  The decimal value. Unsigned values larger than the maximum bigint
+//	  value are interpreted as numeric by PostgreSQL.
+//	float32, float64
 //	  If the value represents +Inf, -Inf or Nan then the literal will be
-//	  'Infinity', '-Infinity' or 'Nan' respectively. Otherwise the %g
-//	  encoding provided by fmt.Printf is used.
+//	  'Infinity', '-Infinity' or 'Nan' respectively. Otherwise the
+//	  shortest decimal representation that uniquely identifies the value
+//	  at the type's precision is used.
 //	string
 //	  A string literal.
 //	[]byte
 //	  A bytea hex format literal, see
 //	  https://www.postgresql.org/docs/13/datatype-binary.html#id-1.5.7.12.9.
+//	net.IP, netip.Addr
+//	  A string literal containing the address, cast to inet. A nil
+//	  net.IP or the zero netip.Addr is formatted as NULL.
+//	net.IPNet, netip.Prefix
+//	  A string literal containing the network in CIDR notation, cast to
+//	  cidr. If the address has bits set to the right of the mask then it
+//	  is cast to inet instead.
+//	net.HardwareAddr
+//	  A string literal containing the address, cast to macaddr, or to
+//	  macaddr8 for 8 byte addresses.
+//	[16]byte
+//	  A string literal containing the value in the standard UUID form,
+//	  cast to uuid. This includes named types such as UUID types from
+//	  other packages, unless they implement driver.Valuer.
 //	time.Time
 //	  A string literal containing the RFC3339 encoding of the time stamp.
+//	  Time stamps that are not in UTC are formatted as specified by
+//	  TimeZones.
+//	time.Duration
+//	  A string literal containing the duration, cast to interval, for
+//	  example '1 hour 30 minutes'::interval.
+//	big.Int
+//	  The decimal value.
+//	big.Rat
+//	  The exact decimal value if there is one, otherwise a division of
+//	  the numerator, cast to numeric, by the denominator, for example
+//	  (1::numeric / 3).
+//	big.Float
+//	  The shortest decimal representation that uniquely identifies the
+//	  value at its precision. Infinite values result in an error as they
+//	  cannot be represented as numeric.
+//	Date
+//	  A string literal containing the date, cast to date.
+//	TimeOfDay
+//	  A string literal containing the time of day, cast to time.
+//	Timestamp
+//	  A string literal containing the date and time of day, cast to
+//	  timestamp. The location of the time stamp is ignored.
+//	TimestampTZ
+//	  As for time.Time, cast to timestamptz.
+//	TrustedSQL
+//	  The SQL text, verbatim. This takes precedence over the Registry.
 //	Identifier
 //	  A quoted identifier, see
 //	  https://www.postgresql.org/docs/13/sql-syntax-lexical.html#SQL-SYNTAX-IDENTIFIERS.
+//	Interval
+//	  As for time.Duration, with the months and days kept separately.
+//	Range
+//	  A call to the constructor function of the range type with each
+//	  of the bounds formatted as a literal, for example
+//	  int8range(1, 10, '[)'). Unbounded ends are passed as NULL and an
+//	  empty range is formatted as 'empty' cast to the range type.
+//	Multirange
+//	  A call to the constructor function of the multirange type with
+//	  each of the ranges as arguments, for example
+//	  int8multirange(int8range(1, 10, '[)')).
+//	map[string]string, map[string]*string
+//	  A string literal containing the map in the hstore format, cast to
+//	  hstore, with the keys in sorted order. A nil map is formatted as
+//	  NULL.
+//	Row
+//	  A ROW constructor containing each of the fields formatted as a
+//	  literal, cast to the composite type if one is specified, for
+//	  example ROW(1, 'a')::my_type.
+//	structs with sql tags
+//	  As for Row, without a cast. Only structs that have at least one
+//	  field with an sql struct tag are formatted this way, other
+//	  structs must be wrapped in a Row.
+//	JSON
+//	  A string literal containing the encoded document, cast to jsonb.
+//	  This takes precedence over the Value method.
+//	json.RawMessage
+//	  A string literal containing the message, cast to jsonb.
+//	slices and arrays
+//	  An ARRAY constructor containing each of the elements formatted as a
+//	  literal, for example ARRAY[1, 2, 3]. Nested slices produce
+//	  multi-dimensional arrays. Empty arrays are cast to the array type
+//	  matching the element type if it is one of the types above,
+//	  otherwise they are formatted as '{}'. A nil slice is formatted as
+//	  NULL. Slices of bytes are formatted as bytea, not arrays.
+//	Array
+//	  As for slices and arrays, but the value is always cast to an array
+//	  of the specified element type, for example ARRAY[1, 2]::integer[].
+//
+// Values of other types with an underlying type of bool, string, or any
+// of the numeric types are formatted as their underlying type.
 func PostgresLiteral(v interface{}) (RawSQL, error) {
-	if dv, ok := v.(driver.Valuer); ok {
-		var err error
-		v, err = dv.Value()
-		if err != nil {
-			return "", err
+	return Postgres{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// PostgresLiteral, unless the Registry has an encoder for v.
+func (d Postgres) Literal(v interface{}) (RawSQL, error) {
+	if d.depth >= postgresMaxDepth {
+		return "", fmt.Errorf("cannot format %T nested more than %d levels deep, the value might contain a cycle", v, postgresMaxDepth)
+	}
+	d.depth++
+	if t, ok := v.(TrustedSQL); ok {
+		return RawSQL(t.s), nil
+	}
+	if s, ok, err := d.Registry.encode(v); ok {
+		return s, err
+	}
+	if f, ok := v.(postgresFormatter); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return RawSQL("NULL"), nil
 		}
+		return f.postgresLiteral(d)
+	}
+	if s, ok, err := postgresLiteralBig(v); ok {
+		return s, err
+	}
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
 	}
 	switch v1 := v.(type) {
+	case nil:
+		return RawSQL("NULL"), nil
 	case RawSQL:
 		return v1, nil
 	case Identifier:
-		return RawSQL(`"` + strings.ReplaceAll(string(v1), `"`, `""`) + `"`), nil
-	case *bool:
-		if v1 == nil {
-			return RawSQL("NULL"), nil
-		}
-		return postgresLiteralBool(*v1), nil
+		return d.QuoteIdentifier(string(v1))
 	case bool:
 		return postgresLiteralBool(v1), nil
 	case []byte:
@@ -61,47 +252,421 @@ func PostgresLiteral(v interface{}) (RawSQL, error) {
 			return RawSQL("NULL"), nil
 		}
 		return RawSQL(fmt.Sprintf("'\\x%X'", v1)), nil
-	case *float64:
+	case net.IP:
 		if v1 == nil {
 			return RawSQL("NULL"), nil
 		}
-		return postgresLiteralFloat(*v1), nil
-	case float64:
-		return postgresLiteralFloat(v1), nil
-	case *int:
-		if v1 == nil {
-			return RawSQL("NULL"), nil
+		if len(v1) != net.IPv4len && len(v1) != net.IPv6len {
+			return "", fmt.Errorf("invalid IP address %v", v1)
 		}
-		return RawSQL(fmt.Sprintf("%d", *v1)), nil
-	case int:
-		return RawSQL(fmt.Sprintf("%d", v1)), nil
-	case *int64:
-		if v1 == nil {
+		return postgresLiteralString(v1.String()) + "::inet", nil
+	case net.IPNet:
+		p, err := ipNetPrefix(v1)
+		if err != nil {
+			return "", err
+		}
+		return postgresLiteralPrefix(p)
+	case netip.Addr:
+		if !v1.IsValid() {
 			return RawSQL("NULL"), nil
 		}
-		return RawSQL(fmt.Sprintf("%d", *v1)), nil
-	case int64:
-		return RawSQL(fmt.Sprintf("%d", v1)), nil
-	case nil:
-		return RawSQL("NULL"), nil
-	case *string:
-		if v1 == nil {
+		if v1.Zone() != "" {
+			return "", fmt.Errorf("cannot represent IP address %v with zone", v1)
+		}
+		return postgresLiteralString(v1.String()) + "::inet", nil
+	case netip.Prefix:
+		if !v1.IsValid() {
 			return RawSQL("NULL"), nil
 		}
-		return RawSQL(`'` + strings.ReplaceAll(*v1, `'`, `''`) + `'`), nil
+		return postgresLiteralPrefix(v1)
+	case net.HardwareAddr:
+		switch len(v1) {
+		case 0:
+			if v1 == nil {
+				return RawSQL("NULL"), nil
+			}
+		case 6:
+			return postgresLiteralString(v1.String()) + "::macaddr", nil
+		case 8:
+			return postgresLiteralString(v1.String()) + "::macaddr8", nil
+		}
+		return "", fmt.Errorf("invalid hardware address %v", v1)
+	case float64:
+		return postgresLiteralFloat(v1, 64), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
 	case string:
-		return RawSQL(`'` + strings.ReplaceAll(v1, `'`, `''`) + `'`), nil
-	case *time.Time:
+		return postgresLiteralString(v1), nil
+	case time.Time:
+		return d.timestamp(v1)
+	case time.Duration:
+		us, ns := int64(v1/time.Microsecond), int64(v1%time.Microsecond)
+		return postgresLiteralString(formatInterval(0, 0, us, ns)) + "::interval", nil
+	case json.RawMessage:
 		if v1 == nil {
 			return RawSQL("NULL"), nil
 		}
-		return RawSQL(`'` + (*v1).Format(time.RFC3339Nano) + `'`), nil
-	case time.Time:
-		return RawSQL(`'` + v1.Format(time.RFC3339Nano) + `'`), nil
+		return postgresLiteralString(string(v1)) + "::jsonb", nil
+	case big.Int:
+		// Values passed as pointers are formatted before indirect
+		// copies them, see postgresLiteralBig. This value was copied
+		// by the caller.
+		return RawSQL(v1.String()), nil
+	case big.Rat:
+		return postgresLiteralRat(&v1), nil
+	case big.Float:
+		if v1.IsInf() {
+			return "", fmt.Errorf("cannot represent %v as numeric", &v1)
+		}
+		return RawSQL(v1.Text('g', -1)), nil
+	}
+	// Named types are formatted according to their underlying kind.
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return postgresLiteralBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return RawSQL(strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// Values larger than math.MaxInt64 are interpreted as
+		// numeric constants by PostgreSQL.
+		return RawSQL(strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32:
+		return postgresLiteralFloat(rv.Float(), 32), nil
+	case reflect.Float64:
+		return postgresLiteralFloat(rv.Float(), 64), nil
+	case reflect.String:
+		return postgresLiteralString(rv.String()), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return d.Literal(rv.Bytes())
+		}
+		return d.array(rv, "")
+	case reflect.Array:
+		if isUUIDType(rv.Type()) {
+			var u [16]byte
+			reflect.Copy(reflect.ValueOf(u[:]), rv)
+			return postgresLiteralUUID(u), nil
+		}
+		return d.array(rv, "")
+	case reflect.Struct:
+		if hasSQLTags(rv.Type()) {
+			return d.Literal(Row{Struct: v})
+		}
+	case reflect.Map:
+		if isHstoreType(rv.Type()) {
+			return postgresLiteralHstore(rv), nil
+		}
 	}
 	return "", fmt.Errorf("unknown type %T", v)
 }
 
+// arg implements argConverter. Values of time.Duration are converted
+// to the interval text form, rather than a number of nanoseconds, and
+// network addresses are converted to their text form, rather than bytes
+// that would be interpreted as bytea. Slices and arrays are converted to
+// Array values, arrays that hold UUIDs are converted to their text form,
+// and maps that can be formatted as hstore values are converted to the
+// hstore text form. Other maps cannot be passed as arguments. Structs
+// with sql tags are converted to Row values. Values of big.Int, big.Rat
+// and big.Float are converted to their exact decimal text, a big.Rat
+// that has no exact decimal form is formatted inline. Unsigned integers
+// larger than math.MaxInt64, which database/sql rejects, are converted to
+// their decimal text. Values that would be formatted, in whole or in
+// part, by an encoder in the Registry are formatted inline, as encoders
+// produce SQL rather than values.
+func (d Postgres) arg(v interface{}) (interface{}, error) {
+	if d.Registry.usedBy(reflect.ValueOf(v), 0) {
+		s, err := d.Literal(v)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if x, ok := bigPointer(v); ok {
+		return postgresArgBig(x)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() && !rv.Type().Implements(valuerType) {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Type().Implements(valuerType) || rv.Kind() == reflect.Ptr {
+		return v, nil
+	}
+	switch v1 := rv.Interface().(type) {
+	case time.Duration:
+		us, ns := int64(v1/time.Microsecond), int64(v1%time.Microsecond)
+		return formatInterval(0, 0, us, ns), nil
+	case big.Int:
+		return postgresArgBig(&v1)
+	case big.Rat:
+		return postgresArgBig(&v1)
+	case big.Float:
+		return postgresArgBig(&v1)
+	case net.IP:
+		if v1 == nil {
+			return nil, nil
+		}
+		if len(v1) != net.IPv4len && len(v1) != net.IPv6len {
+			return nil, fmt.Errorf("invalid IP address %v", v1)
+		}
+		return v1.String(), nil
+	case net.IPNet:
+		p, err := ipNetPrefix(v1)
+		if err != nil {
+			return nil, err
+		}
+		return p.String(), nil
+	case netip.Addr:
+		if !v1.IsValid() {
+			return nil, nil
+		}
+		if v1.Zone() != "" {
+			return nil, fmt.Errorf("cannot represent IP address %v with zone", v1)
+		}
+		return v1.String(), nil
+	case netip.Prefix:
+		if !v1.IsValid() {
+			return nil, nil
+		}
+		if v1.Addr().Zone() != "" {
+			return nil, fmt.Errorf("cannot represent IP network %v with zone", v1)
+		}
+		return v1.String(), nil
+	case net.HardwareAddr:
+		if v1 == nil {
+			return nil, nil
+		}
+		if len(v1) != 6 && len(v1) != 8 {
+			return nil, fmt.Errorf("invalid hardware address %v", v1)
+		}
+		return v1.String(), nil
+	}
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v, nil
+		}
+		return Array{Elems: rv.Interface()}, nil
+	case reflect.Array:
+		if isUUIDType(rv.Type()) {
+			var u [16]byte
+			reflect.Copy(reflect.ValueOf(u[:]), rv)
+			return formatUUID(u), nil
+		}
+		return Array{Elems: rv.Interface()}, nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u > math.MaxInt64 {
+			return strconv.FormatUint(u, 10), nil
+		}
+	case reflect.Struct:
+		if hasSQLTags(rv.Type()) {
+			return Row{Struct: v}, nil
+		}
+	case reflect.Map:
+		if !isHstoreType(rv.Type()) {
+			return nil, fmt.Errorf("unknown type %T", v)
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return formatHstore(rv), nil
+	}
+	return v, nil
+}
+
+// array formats the slice or array held in rv as an ARRAY constructor.
+// If elemType is not empty then the array is cast to an array of that
+// type.
+func (d Postgres) array(rv reflect.Value, elemType string) (RawSQL, error) {
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return RawSQL("NULL"), nil
+	}
+	if rv.Len() == 0 {
+		if elemType == "" {
+			elemType = postgresArrayElemType(rv.Type().Elem())
+		}
+		if elemType == "" {
+			return RawSQL("'{}'"), nil
+		}
+		return RawSQL("ARRAY[]::" + elemType + "[]"), nil
+	}
+	var sb strings.Builder
+	sb.WriteString("ARRAY[")
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		s, err := d.Literal(elemInterface(rv.Index(i)))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(s))
+	}
+	sb.WriteString("]")
+	if elemType != "" {
+		sb.WriteString("::" + elemType + "[]")
+	}
+	return RawSQL(sb.String()), nil
+}
+
+// postgresArrayElemTypes maps Go types to the PostgreSQL type used for
+// the elements of an empty array.
+var postgresArrayElemTypes = map[reflect.Type]string{
+	reflect.TypeOf(big.Float{}):           "numeric",
+	reflect.TypeOf(big.Int{}):             "numeric",
+	reflect.TypeOf(big.Rat{}):             "numeric",
+	reflect.TypeOf([]byte(nil)):           "bytea",
+	reflect.TypeOf(time.Time{}):           "timestamptz",
+	reflect.TypeOf(time.Duration(0)):      "interval",
+	reflect.TypeOf(Interval{}):            "interval",
+	reflect.TypeOf(net.IP(nil)):           "inet",
+	reflect.TypeOf(net.IPNet{}):           "cidr",
+	reflect.TypeOf(netip.Addr{}):          "inet",
+	reflect.TypeOf(netip.Prefix{}):        "cidr",
+	reflect.TypeOf(net.HardwareAddr(nil)): "macaddr",
+	reflect.TypeOf(Date{}):                "date",
+	reflect.TypeOf(TimeOfDay{}):           "time",
+	reflect.TypeOf(Timestamp{}):           "timestamp",
+	reflect.TypeOf(TimestampTZ{}):         "timestamptz",
+}
+
+// postgresArrayElemKinds maps the kinds of Go types to the PostgreSQL
+// type used for the elements of an empty array.
+var postgresArrayElemKinds = map[reflect.Kind]string{
+	reflect.Bool:    "boolean",
+	reflect.Int:     "bigint",
+	reflect.Int8:    "smallint",
+	reflect.Int16:   "smallint",
+	reflect.Int32:   "integer",
+	reflect.Int64:   "bigint",
+	reflect.Uint:    "numeric",
+	reflect.Uint8:   "smallint",
+	reflect.Uint16:  "integer",
+	reflect.Uint32:  "bigint",
+	reflect.Uint64:  "numeric",
+	reflect.Uintptr: "numeric",
+	reflect.Float32: "real",
+	reflect.Float64: "double precision",
+	reflect.String:  "text",
+}
+
+// postgresArrayElemType determines the PostgreSQL type of the elements
+// in an array with the Go element type t. Pointers and nested arrays are
+// followed to find the type of the innermost elements. If the type
+// cannot be determined then an empty string is returned.
+func postgresArrayElemType(t reflect.Type) string {
+	for {
+		if typ, ok := postgresArrayElemTypes[t]; ok {
+			return typ
+		}
+		if t == reflect.TypeOf(Identifier("")) || t == reflect.TypeOf(RawSQL("")) {
+			return ""
+		}
+		if isUUIDType(t) {
+			return "uuid"
+		}
+		if isHstoreType(t) {
+			return "hstore"
+		}
+		if typ, ok := postgresArrayElemKinds[t.Kind()]; ok {
+			return typ
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+}
+
+// timestamp formats t as a string literal, applying the TimeZones
+// policy.
+func (d Postgres) timestamp(t time.Time) (RawSQL, error) {
+	if _, offset := t.Zone(); offset != 0 {
+		switch d.TimeZones {
+		case PostgresTimeZonesUTC:
+			t = t.UTC()
+		case PostgresTimeZonesError:
+			return "", fmt.Errorf("time %v is not in UTC", t)
+		}
+	}
+	return RawSQL(`'` + t.Format(time.RFC3339Nano) + `'`), nil
+}
+
+// postgresLiteralBig formats v if it is a pointer, possibly through
+// further pointers, to a big.Int, big.Rat or big.Float. These are
+// formatted through the pointer, as math/big does not support copying
+// them, so this must be done before indirect follows the pointers.
+func postgresLiteralBig(v interface{}) (_ RawSQL, ok bool, _ error) {
+	x, ok := bigPointer(v)
+	if !ok {
+		return "", false, nil
+	}
+	switch x := x.(type) {
+	case *big.Int:
+		return RawSQL(x.String()), true, nil
+	case *big.Rat:
+		return postgresLiteralRat(x), true, nil
+	case *big.Float:
+		if x.IsInf() {
+			return "", true, fmt.Errorf("cannot represent %v as numeric", x)
+		}
+		return RawSQL(x.Text('g', -1)), true, nil
+	}
+	panic("unreachable")
+}
+
+// bigPointer returns the *big.Int, *big.Rat or *big.Float that v points
+// to, possibly through further pointers. It returns false if v does not
+// point to one of these.
+func bigPointer(v interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		switch x := rv.Interface().(type) {
+		case *big.Int, *big.Rat, *big.Float:
+			return x, true
+		}
+		rv = rv.Elem()
+	}
+	return nil, false
+}
+
+// postgresArgBig converts x, which is a *big.Int, *big.Rat or
+// *big.Float, to a query argument holding its exact decimal text.
+// PostgreSQL cannot parse a fraction, so a big.Rat that has no exact
+// decimal form is instead formatted inline as a numeric division.
+func postgresArgBig(x interface{}) (interface{}, error) {
+	if r, ok := x.(*big.Rat); ok {
+		if s, ok := ratDecimal(r); ok {
+			return s, nil
+		}
+		return postgresLiteralRat(r), nil
+	}
+	s, _, err := postgresLiteralBig(x)
+	if err != nil {
+		return nil, err
+	}
+	return string(s), nil
+}
+
+// elemInterface returns the value held in rv, which is an element of
+// a composite value. If rv is addressable and holds a big.Int, big.Rat
+// or big.Float then a pointer to it is returned instead, so that the
+// value is not copied.
+func elemInterface(rv reflect.Value) interface{} {
+	if rv.CanAddr() {
+		switch rv.Type() {
+		case reflect.TypeOf(big.Int{}), reflect.TypeOf(big.Rat{}), reflect.TypeOf(big.Float{}):
+			return rv.Addr().Interface()
+		}
+	}
+	return rv.Interface()
+}
+
 func postgresLiteralBool(b bool) RawSQL {
 	if b {
 		return RawSQL("TRUE")
@@ -109,7 +674,9 @@ func postgresLiteralBool(b bool) RawSQL {
 	return RawSQL("FALSE")
 }
 
-func postgresLiteralFloat(f float64) RawSQL {
+// postgresLiteralFloat formats f, which holds a floating point value with
+// the given size in bits, as a literal.
+func postgresLiteralFloat(f float64, bitSize int) RawSQL {
 	if math.IsInf(f, 1) {
 		return RawSQL("'Infinity'")
 	}
@@ -119,5 +686,425 @@ func postgresLiteralFloat(f float64) RawSQL {
 	if math.IsNaN(f) {
 		return RawSQL("'NaN'")
 	}
-	return RawSQL(fmt.Sprintf("%g", f))
+	return RawSQL(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// postgresLiteralRat formats r as a numeric literal. If r can be
+// represented exactly as a decimal then that is used, otherwise r is
+// formatted as a numeric division, which PostgreSQL evaluates to a
+// limited number of decimal places.
+func postgresLiteralRat(r *big.Rat) RawSQL {
+	if s, ok := ratDecimal(r); ok {
+		return RawSQL(s)
+	}
+	return RawSQL("(" + r.Num().String() + "::numeric / " + r.Denom().String() + ")")
+}
+
+// ratDecimal formats r as an exact decimal number. It returns false if r
+// does not have a terminating decimal expansion.
+func ratDecimal(r *big.Rat) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+	// A fraction in its lowest terms has a terminating decimal
+	// expansion only if the denominator has no prime factors other
+	// than 2 and 5.
+	d := new(big.Int).Set(r.Denom())
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+	var fives int
+	five := big.NewInt(5)
+	q, m := new(big.Int), new(big.Int)
+	for {
+		q.QuoRem(d, five, m)
+		if m.Sign() != 0 {
+			break
+		}
+		d.Set(q)
+		fives++
+	}
+	if d.IsInt64() && d.Int64() == 1 {
+		prec := twos
+		if fives > prec {
+			prec = fives
+		}
+		return r.FloatString(prec), true
+	}
+	return "", false
+}
+
+// postgresLiteralHstore formats the map held in rv, which must satisfy
+// isHstoreType, as an hstore literal.
+func postgresLiteralHstore(rv reflect.Value) RawSQL {
+	if rv.IsNil() {
+		return RawSQL("NULL")
+	}
+	return postgresLiteralString(formatHstore(rv)) + "::hstore"
+}
+
+// formatHstore formats the map held in rv, which must satisfy
+// isHstoreType, in the hstore text format with the keys in sorted order.
+func formatHstore(rv reflect.Value) string {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeQuotedText(&sb, k.String())
+		sb.WriteString("=>")
+		v := rv.MapIndex(k)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				sb.WriteString("NULL")
+				continue
+			}
+			v = v.Elem()
+		}
+		writeQuotedText(&sb, v.String())
+	}
+	return sb.String()
+}
+
+// isHstoreType reports whether t is a map type with string keys and
+// either string or pointer to string values.
+func isHstoreType(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+	e := t.Elem()
+	if e.Kind() == reflect.Ptr {
+		e = e.Elem()
+	}
+	return e.Kind() == reflect.String
+}
+
+// ipNetPrefix converts n to a netip.Prefix. IPv4 networks held in
+// 16-byte addresses are unmapped.
+func ipNetPrefix(n net.IPNet) (netip.Prefix, error) {
+	addr, ok := netip.AddrFromSlice(n.IP)
+	ones, bits := n.Mask.Size()
+	if bits == 8*net.IPv4len {
+		addr = addr.Unmap()
+	}
+	if !ok || bits != addr.BitLen() {
+		return netip.Prefix{}, fmt.Errorf("invalid IP network %v with mask %v", n.IP, n.Mask)
+	}
+	return netip.PrefixFrom(addr, ones), nil
+}
+
+// postgresLiteralPrefix formats p as a cidr literal, or as an inet
+// literal if the address has bits set outside of the prefix.
+func postgresLiteralPrefix(p netip.Prefix) (RawSQL, error) {
+	if p.Addr().Zone() != "" {
+		return "", fmt.Errorf("cannot represent IP network %v with zone", p)
+	}
+	if p.Masked() != p {
+		return postgresLiteralString(p.String()) + "::inet", nil
+	}
+	return postgresLiteralString(p.String()) + "::cidr", nil
+}
+
+// postgresLiteralUUID formats u as a uuid literal.
+func postgresLiteralUUID(u [16]byte) RawSQL {
+	return RawSQL("'" + formatUUID(u) + "'::uuid")
+}
+
+// isUUIDType reports whether t has an underlying type of [16]byte.
+func isUUIDType(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8
+}
+
+func postgresLiteralString(s string) RawSQL {
+	return RawSQL(`'` + strings.ReplaceAll(s, `'`, `''`) + `'`)
+}
+
+// A postgresFormatter is implemented by types in this package that have a
+// more specific literal form in PostgreSQL than the value returned by
+// their Value method.
+type postgresFormatter interface {
+	postgresLiteral(d Postgres) (RawSQL, error)
+}
+
+func (j JSON) postgresLiteral(Postgres) (RawSQL, error) {
+	buf, err := json.Marshal(j.Data)
+	if err != nil {
+		return "", err
+	}
+	return postgresLiteralString(string(buf)) + "::jsonb", nil
+}
+
+func (a Array) postgresLiteral(d Postgres) (RawSQL, error) {
+	rv, err := a.elems()
+	if err != nil {
+		return "", err
+	}
+	if a.ElemType != "" {
+		if err := checkPostgresTypeName(a.ElemType); err != nil {
+			return "", err
+		}
+	}
+	return d.array(rv, a.ElemType)
+}
+
+func (i Interval) postgresLiteral(Postgres) (RawSQL, error) {
+	return postgresLiteralString(formatInterval(int64(i.Months), int64(i.Days), i.Microseconds, 0)) + "::interval", nil
+}
+
+func (t Date) postgresLiteral(Postgres) (RawSQL, error) {
+	return RawSQL(`'` + time.Time(t).Format("2006-01-02") + `'::date`), nil
+}
+
+func (t TimeOfDay) postgresLiteral(Postgres) (RawSQL, error) {
+	return RawSQL(`'` + time.Time(t).Format("15:04:05.999999999") + `'::time`), nil
+}
+
+func (t Timestamp) postgresLiteral(Postgres) (RawSQL, error) {
+	return RawSQL(`'` + time.Time(t).Format("2006-01-02 15:04:05.999999999") + `'::timestamp`), nil
+}
+
+func (t TimestampTZ) postgresLiteral(d Postgres) (RawSQL, error) {
+	s, err := d.timestamp(time.Time(t))
+	if err != nil {
+		return "", err
+	}
+	return s + "::timestamptz", nil
+}
+
+func (r Range[T]) postgresLiteral(d Postgres) (RawSQL, error) {
+	typ := r.Type
+	if typ != "" {
+		if err := checkPostgresTypeName(typ); err != nil {
+			return "", err
+		}
+	} else {
+		typ = postgresRangeType(reflect.TypeOf((*T)(nil)).Elem())
+		if typ == "" {
+			var zero T
+			return "", fmt.Errorf("cannot determine range type for %T", zero)
+		}
+	}
+	if r.Empty {
+		return RawSQL("'empty'::" + typ), nil
+	}
+	lower, upper := d.Null(), d.Null()
+	var err error
+	if !r.LowerUnbounded {
+		if lower, err = d.Literal(r.Lower); err != nil {
+			return "", err
+		}
+	}
+	if !r.UpperUnbounded {
+		if upper, err = d.Literal(r.Upper); err != nil {
+			return "", err
+		}
+	}
+	return RawSQL(typ+"(") + lower + ", " + upper + ", '" + RawSQL(r.bounds()) + "')", nil
+}
+
+func (m Multirange[T]) postgresLiteral(d Postgres) (RawSQL, error) {
+	typ := m.Type
+	if typ != "" {
+		if err := checkPostgresTypeName(typ); err != nil {
+			return "", err
+		}
+	} else {
+		typ = postgresRangeType(reflect.TypeOf((*T)(nil)).Elem())
+		if typ == "" {
+			var zero T
+			return "", fmt.Errorf("cannot determine multirange type for %T", zero)
+		}
+		typ = strings.TrimSuffix(typ, "range") + "multirange"
+	}
+	var sb strings.Builder
+	sb.WriteString(typ + "(")
+	for i, r := range m.Ranges {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		s, err := r.postgresLiteral(d)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(s))
+	}
+	sb.WriteString(")")
+	return RawSQL(sb.String()), nil
+}
+
+func (r Row) postgresLiteral(d Postgres) (RawSQL, error) {
+	if r.Type != "" {
+		if err := checkPostgresTypeName(r.Type); err != nil {
+			return "", err
+		}
+	}
+	fields, err := r.fields()
+	if err != nil {
+		return "", err
+	}
+	if fields == nil {
+		return d.Null(), nil
+	}
+	var sb strings.Builder
+	sb.WriteString("ROW(")
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		s, err := d.Literal(elemInterface(f))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(string(s))
+	}
+	sb.WriteString(")")
+	if r.Type != "" {
+		sb.WriteString("::" + r.Type)
+	}
+	return RawSQL(sb.String()), nil
+}
+
+// postgresRangeTypes maps Go types to the PostgreSQL range type with
+// elements of that type.
+var postgresRangeTypes = map[reflect.Type]string{
+	reflect.TypeOf(big.Float{}):   "numrange",
+	reflect.TypeOf(big.Int{}):     "numrange",
+	reflect.TypeOf(big.Rat{}):     "numrange",
+	reflect.TypeOf(time.Time{}):   "tstzrange",
+	reflect.TypeOf(Date{}):        "daterange",
+	reflect.TypeOf(Timestamp{}):   "tsrange",
+	reflect.TypeOf(TimestampTZ{}): "tstzrange",
+}
+
+// postgresRangeKinds maps the kinds of Go types to the PostgreSQL range
+// type with elements of that type.
+var postgresRangeKinds = map[reflect.Kind]string{
+	reflect.Int:     "int8range",
+	reflect.Int8:    "int4range",
+	reflect.Int16:   "int4range",
+	reflect.Int32:   "int4range",
+	reflect.Int64:   "int8range",
+	reflect.Uint:    "numrange",
+	reflect.Uint8:   "int4range",
+	reflect.Uint16:  "int4range",
+	reflect.Uint32:  "int8range",
+	reflect.Uint64:  "numrange",
+	reflect.Uintptr: "numrange",
+	reflect.Float32: "numrange",
+	reflect.Float64: "numrange",
+}
+
+// postgresRangeType determines the PostgreSQL range type with elements
+// of the Go type t, following pointers. If the type cannot be determined
+// then an empty string is returned.
+func postgresRangeType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if typ, ok := postgresRangeTypes[t]; ok {
+		return typ
+	}
+	return postgresRangeKinds[t.Kind()]
+}
+
+// checkPostgresTypeName checks that name is a valid PostgreSQL type
+// name. Type names are written into the output verbatim,
+// so only a strict subset of the type name syntax is allowed: an
+// identifier, optionally qualified with a schema name, followed by
+// optional type modifiers and array bounds, for example
+// "public.item", "numeric(10, 2)" or "text[]". The multi-word names of
+// built-in types, such as "double precision" and "timestamp with time
+// zone", are also allowed.
+func checkPostgresTypeName(name string) error {
+	if !validPostgresTypeName(name) {
+		return fmt.Errorf("invalid SQL type name %q", name)
+	}
+	return nil
+}
+
+// postgresTypeWords maps the first word of the multi-word names of
+// built-in types to the words that may follow it.
+var postgresTypeWords = map[string]string{
+	"bit":       " varying",
+	"character": " varying",
+	"double":    " precision",
+}
+
+func validPostgresTypeName(s string) bool {
+	n := scanIdentifier(s)
+	if n == 0 {
+		return false
+	}
+	word := strings.ToLower(s[:n])
+	s = s[n:]
+	if strings.HasPrefix(s, ".") {
+		if n = scanIdentifier(s[1:]); n == 0 {
+			return false
+		}
+		s, word = s[n+1:], ""
+	} else if w, ok := postgresTypeWords[word]; ok && hasPrefixFold(s, w) {
+		s = s[len(w):]
+	}
+	if strings.HasPrefix(s, "(") {
+		i := strings.IndexByte(s, ')')
+		if i < 0 {
+			return false
+		}
+		for _, m := range strings.Split(s[1:i], ",") {
+			if !isDigits(strings.TrimSpace(m)) {
+				return false
+			}
+		}
+		s = s[i+1:]
+	}
+	if word == "time" || word == "timestamp" {
+		for _, w := range []string{" with time zone", " without time zone"} {
+			if hasPrefixFold(s, w) {
+				s = s[len(w):]
+				break
+			}
+		}
+	}
+	for strings.HasPrefix(s, "[") {
+		i := strings.IndexByte(s, ']')
+		if i < 0 || (i > 1 && !isDigits(s[1:i])) {
+			return false
+		}
+		s = s[i+1:]
+	}
+	return s == ""
+}
+
+// scanIdentifier returns the length of the unquoted identifier at the
+// start of s, or 0 if s does not start with an identifier.
+func scanIdentifier(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case i > 0 && (c == '$' || '0' <= c && c <= '9'):
+		default:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
 }
@@ -2,7 +2,13 @@ package sqltemplate
 
 import (
 	"database/sql"
+	"encoding/json"
 	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -157,8 +163,542 @@ var postgresLiteralTests = []struct {
 		Time:  time.Date(2020, time.February, 2, 12, 30, 45, 300005000, time.FixedZone("UTC-3", -3*60*60)),
 	},
 	expectSQL: `'2020-02-02T12:30:45.300005-03:00'`,
+}, {
+	name:      "string slice",
+	value:     []string{"a", "b'c"},
+	expectSQL: `ARRAY['a', 'b''c']`,
+}, {
+	name:      "int64 slice",
+	value:     []int64{1, 2, 3},
+	expectSQL: `ARRAY[1, 2, 3]`,
+}, {
+	name:      "time slice",
+	value:     []time.Time{time.Date(2020, time.February, 2, 12, 30, 45, 0, time.UTC)},
+	expectSQL: `ARRAY['2020-02-02T12:30:45Z']`,
+}, {
+	name:      "bytes slice",
+	value:     [][]byte{[]byte("test"), nil},
+	expectSQL: `ARRAY['\x74657374', NULL]`,
+}, {
+	name:      "pointer slice",
+	value:     []*string{newString("a"), nil},
+	expectSQL: `ARRAY['a', NULL]`,
+}, {
+	name:      "interface slice",
+	value:     []interface{}{1, "a", nil},
+	expectSQL: `ARRAY[1, 'a', NULL]`,
+}, {
+	name:      "nested slice",
+	value:     [][]int{{1, 2}, {3, 4}},
+	expectSQL: `ARRAY[ARRAY[1, 2], ARRAY[3, 4]]`,
+}, {
+	name:      "array",
+	value:     [2]float64{1.5, 2.5},
+	expectSQL: `ARRAY[1.5, 2.5]`,
+}, {
+	name:      "empty string slice",
+	value:     []string{},
+	expectSQL: `ARRAY[]::text[]`,
+}, {
+	name:      "empty nested pointer slice",
+	value:     [][]*int64{},
+	expectSQL: `ARRAY[]::bigint[]`,
+}, {
+	name:      "empty slice of unknown type",
+	value:     []interface{}{},
+	expectSQL: `'{}'`,
+}, {
+	name:      "nil slice",
+	value:     []string(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "named bytes",
+	value:     testBytes("test"),
+	expectSQL: `'\x74657374'`,
+}, {
+	name:      "typed array",
+	value:     Array{Elems: []int{1, 2}, ElemType: "integer"},
+	expectSQL: `ARRAY[1, 2]::integer[]`,
+}, {
+	name:      "empty typed array",
+	value:     Array{Elems: []interface{}{}, ElemType: "integer"},
+	expectSQL: `ARRAY[]::integer[]`,
+}, {
+	name:      "array with qualified element type",
+	value:     Array{Elems: []string{"a"}, ElemType: "public.item_name"},
+	expectSQL: `ARRAY['a']::public.item_name[]`,
+}, {
+	name:      "array with multi-word element type",
+	value:     Array{Elems: []float64{1}, ElemType: "double precision"},
+	expectSQL: `ARRAY[1]::double precision[]`,
+}, {
+	name:      "array with element type modifiers",
+	value:     Array{Elems: []float64{1}, ElemType: "numeric(10, 2)"},
+	expectSQL: `ARRAY[1]::numeric(10, 2)[]`,
+}, {
+	name:      "array with array element type",
+	value:     Array{Elems: [][]int{{1}}, ElemType: "timestamp(3) WITH TIME ZONE[2]"},
+	expectSQL: `ARRAY[ARRAY[1]]::timestamp(3) WITH TIME ZONE[2][]`,
+}, {
+	name:      "int8",
+	value:     int8(math.MinInt8),
+	expectSQL: `-128`,
+}, {
+	name:      "int16",
+	value:     int16(math.MaxInt16),
+	expectSQL: `32767`,
+}, {
+	name:      "int32",
+	value:     int32(math.MinInt32),
+	expectSQL: `-2147483648`,
+}, {
+	name:      "int32 pointer",
+	value:     newInt32(-1),
+	expectSQL: `-1`,
+}, {
+	name:      "nil int32 pointer",
+	value:     (*int32)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "uint",
+	value:     uint(7),
+	expectSQL: `7`,
+}, {
+	name:      "uint pointer",
+	value:     newUint(7),
+	expectSQL: `7`,
+}, {
+	name:      "nil uint pointer",
+	value:     (*uint)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "uint8",
+	value:     uint8(math.MaxUint8),
+	expectSQL: `255`,
+}, {
+	name:      "uint16",
+	value:     uint16(math.MaxUint16),
+	expectSQL: `65535`,
+}, {
+	name:      "uint32",
+	value:     uint32(math.MaxUint32),
+	expectSQL: `4294967295`,
+}, {
+	name:      "uint64",
+	value:     uint64(math.MaxInt64),
+	expectSQL: `9223372036854775807`,
+}, {
+	name:      "large uint64",
+	value:     uint64(math.MaxUint64),
+	expectSQL: `18446744073709551615`,
+}, {
+	name:      "float32",
+	value:     float32(0.1),
+	expectSQL: `0.1`,
+}, {
+	name:      "float32 Inf",
+	value:     float32(math.Inf(-1)),
+	expectSQL: `'-Infinity'`,
+}, {
+	name:      "named int",
+	value:     testInt(42),
+	expectSQL: `42`,
+}, {
+	name:      "named int pointer",
+	value:     func() *testInt { i := testInt(42); return &i }(),
+	expectSQL: `42`,
+}, {
+	name:      "named uint16",
+	value:     testUint16(42),
+	expectSQL: `42`,
+}, {
+	name:      "named float32",
+	value:     testFloat32(1.5),
+	expectSQL: `1.5`,
+}, {
+	name:      "named string",
+	value:     testString("a'b"),
+	expectSQL: `'a''b'`,
+}, {
+	name:      "named bool",
+	value:     testBool(true),
+	expectSQL: `TRUE`,
+}, {
+	name:      "empty int32 slice",
+	value:     []int32{},
+	expectSQL: `ARRAY[]::integer[]`,
+}, {
+	name:      "empty float32 slice",
+	value:     []float32{},
+	expectSQL: `ARRAY[]::real[]`,
+}, {
+	name:      "empty named string slice",
+	value:     []testString{},
+	expectSQL: `ARRAY[]::text[]`,
+}, {
+	name:      "big int",
+	value:     new(big.Int).Lsh(big.NewInt(1), 100),
+	expectSQL: `1267650600228229401496703205376`,
+}, {
+	name:      "negative big int",
+	value:     big.NewInt(-5),
+	expectSQL: `-5`,
+}, {
+	name:      "nil big int",
+	value:     (*big.Int)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "big rat integer",
+	value:     big.NewRat(10, 2),
+	expectSQL: `5`,
+}, {
+	name:      "big rat decimal",
+	value:     big.NewRat(-123456789, 1000),
+	expectSQL: `-123456.789`,
+}, {
+	name:      "big rat binary fraction",
+	value:     big.NewRat(1, 1<<10),
+	expectSQL: `0.0009765625`,
+}, {
+	name:      "big rat mixed fraction",
+	value:     big.NewRat(3, 40),
+	expectSQL: `0.075`,
+}, {
+	name:      "big rat recurring",
+	value:     big.NewRat(-2, 3),
+	expectSQL: `(-2::numeric / 3)`,
+}, {
+	name:      "nil big rat",
+	value:     (*big.Rat)(nil),
+	expectSQL: `NULL`,
+}, {
+	name: "big float",
+	value: func() *big.Float {
+		f, _, _ := big.ParseFloat("123456789012345678901234567890.123456", 10, 200, big.ToNearestEven)
+		return f
+	}(),
+	expectSQL: `1.23456789012345678901234567890123456e+29`,
+}, {
+	name:      "small big float",
+	value:     big.NewFloat(0.25),
+	expectSQL: `0.25`,
+}, {
+	name:      "nil big float",
+	value:     (*big.Float)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "big int slice",
+	value:     []*big.Int{big.NewInt(1), nil},
+	expectSQL: `ARRAY[1, NULL]`,
+}, {
+	name:      "big int value slice",
+	value:     []big.Int{*big.NewInt(2)},
+	expectSQL: `ARRAY[2]`,
+}, {
+	name:      "big float pointer pointer",
+	value:     func() **big.Float { f := big.NewFloat(1.5); return &f }(),
+	expectSQL: `1.5`,
+}, {
+	name:      "big rat in row",
+	value:     Row{Struct: &struct{ R big.Rat }{R: *big.NewRat(1, 4)}},
+	expectSQL: `ROW(0.25)`,
+}, {
+	name:      "empty big rat slice",
+	value:     []*big.Rat{},
+	expectSQL: `ARRAY[]::numeric[]`,
+}, {
+	name:      "json",
+	value:     JSON{Data: map[string]interface{}{"a": 1, "b": "it's"}},
+	expectSQL: `'{"a":1,"b":"it''s"}'::jsonb`,
+}, {
+	name:      "json pointer",
+	value:     &JSON{Data: []int{1, 2}},
+	expectSQL: `'[1,2]'::jsonb`,
+}, {
+	name:      "json null",
+	value:     JSON{},
+	expectSQL: `'null'::jsonb`,
+}, {
+	name:      "nil json pointer",
+	value:     (*JSON)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "json raw message",
+	value:     json.RawMessage(`{"a": "it's"}`),
+	expectSQL: `'{"a": "it''s"}'::jsonb`,
+}, {
+	name:      "nil json raw message",
+	value:     json.RawMessage(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "duration",
+	value:     90 * time.Minute,
+	expectSQL: `'1 hour 30 minutes'::interval`,
+}, {
+	name:      "fractional duration",
+	value:     2*time.Hour + 1500*time.Millisecond,
+	expectSQL: `'2 hours 1.5 seconds'::interval`,
+}, {
+	name:      "nanosecond duration",
+	value:     time.Second + time.Nanosecond,
+	expectSQL: `'1.000000001 seconds'::interval`,
+}, {
+	name:      "negative duration",
+	value:     -(time.Minute + 500*time.Millisecond),
+	expectSQL: `'-1 minute -0.5 seconds'::interval`,
+}, {
+	name:      "zero duration",
+	value:     time.Duration(0),
+	expectSQL: `'0 seconds'::interval`,
+}, {
+	name:      "duration pointer",
+	value:     func() *time.Duration { d := time.Second; return &d }(),
+	expectSQL: `'1 second'::interval`,
+}, {
+	name:      "interval",
+	value:     Interval{Months: 14, Days: 3, Microseconds: 4*3600000000 + 5000000},
+	expectSQL: `'1 year 2 months 3 days 4 hours 5 seconds'::interval`,
+}, {
+	name:      "mixed sign interval",
+	value:     Interval{Months: 1, Days: -1, Microseconds: -1},
+	expectSQL: `'1 month -1 day -0.000001 seconds'::interval`,
+}, {
+	name:      "nil interval pointer",
+	value:     (*Interval)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "empty duration slice",
+	value:     []time.Duration{},
+	expectSQL: `ARRAY[]::interval[]`,
+}, {
+	name:      "interval slice",
+	value:     []Interval{{Days: 1}, {}},
+	expectSQL: `ARRAY['1 day'::interval, '0 seconds'::interval]`,
+}, {
+	name:      "date",
+	value:     Date(time.Date(2020, time.February, 2, 23, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))),
+	expectSQL: `'2020-02-02'::date`,
+}, {
+	name:      "time of day",
+	value:     TimeOfDay(time.Date(2020, time.February, 2, 12, 30, 45, 300000000, time.UTC)),
+	expectSQL: `'12:30:45.3'::time`,
+}, {
+	name:      "timestamp",
+	value:     Timestamp(time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60))),
+	expectSQL: `'2020-02-02 12:30:45'::timestamp`,
+}, {
+	name:      "timestamptz",
+	value:     TimestampTZ(time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60))),
+	expectSQL: `'2020-02-02T12:30:45-03:00'::timestamptz`,
+}, {
+	name:      "nil date pointer",
+	value:     (*Date)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "empty date slice",
+	value:     []Date{},
+	expectSQL: `ARRAY[]::date[]`,
+}, {
+	name:      "ipv4",
+	value:     net.ParseIP("192.0.2.1"),
+	expectSQL: `'192.0.2.1'::inet`,
+}, {
+	name:      "ipv6",
+	value:     net.ParseIP("2001:db8::1"),
+	expectSQL: `'2001:db8::1'::inet`,
+}, {
+	name:      "nil ip",
+	value:     net.IP(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "ip network",
+	value:     mustParseCIDR("192.0.2.0/24"),
+	expectSQL: `'192.0.2.0/24'::cidr`,
+}, {
+	name:      "ip network with host bits",
+	value:     net.IPNet{IP: net.ParseIP("192.0.2.1"), Mask: net.CIDRMask(24, 32)},
+	expectSQL: `'192.0.2.1/24'::inet`,
+}, {
+	name:      "ipv6 network",
+	value:     mustParseCIDR("2001:db8::/32"),
+	expectSQL: `'2001:db8::/32'::cidr`,
+}, {
+	name:      "addr",
+	value:     netip.MustParseAddr("2001:db8::1"),
+	expectSQL: `'2001:db8::1'::inet`,
+}, {
+	name:      "zero addr",
+	value:     netip.Addr{},
+	expectSQL: `NULL`,
+}, {
+	name:      "prefix",
+	value:     netip.MustParsePrefix("10.0.0.0/8"),
+	expectSQL: `'10.0.0.0/8'::cidr`,
+}, {
+	name:      "prefix with host bits",
+	value:     netip.MustParsePrefix("10.1.2.3/8"),
+	expectSQL: `'10.1.2.3/8'::inet`,
+}, {
+	name:      "hardware address",
+	value:     mustParseMAC("00:00:5e:00:53:01"),
+	expectSQL: `'00:00:5e:00:53:01'::macaddr`,
+}, {
+	name:      "eui64 hardware address",
+	value:     mustParseMAC("02:00:5e:10:00:00:00:01"),
+	expectSQL: `'02:00:5e:10:00:00:00:01'::macaddr8`,
+}, {
+	name:      "uuid",
+	value:     [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+	expectSQL: `'123e4567-e89b-12d3-a456-426614174000'::uuid`,
+}, {
+	name:      "named uuid",
+	value:     &testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+	expectSQL: `'123e4567-e89b-12d3-a456-426614174000'::uuid`,
+}, {
+	name:      "ip slice",
+	value:     []net.IP{net.ParseIP("192.0.2.1"), nil},
+	expectSQL: `ARRAY['192.0.2.1'::inet, NULL]`,
+}, {
+	name:      "empty ip slice",
+	value:     []net.IP{},
+	expectSQL: `ARRAY[]::inet[]`,
+}, {
+	name:      "empty uuid slice",
+	value:     []testUUID{},
+	expectSQL: `ARRAY[]::uuid[]`,
+}, {
+	name:      "int range",
+	value:     Range[int64]{Lower: 1, Upper: 10, LowerInclusive: true},
+	expectSQL: `int8range(1, 10, '[)')`,
+}, {
+	name:      "int32 range",
+	value:     Range[int32]{Lower: 1, Upper: 10, LowerInclusive: true, UpperInclusive: true},
+	expectSQL: `int4range(1, 10, '[]')`,
+}, {
+	name:      "time range",
+	value:     &Range[time.Time]{Lower: time.Date(2020, time.February, 2, 12, 0, 0, 0, time.UTC), LowerInclusive: true, UpperUnbounded: true, UpperInclusive: true},
+	expectSQL: `tstzrange('2020-02-02T12:00:00Z', NULL, '[)')`,
+}, {
+	name:      "date range",
+	value:     Range[Date]{Lower: Date(time.Date(2020, time.February, 2, 0, 0, 0, 0, time.UTC)), Upper: Date(time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)), LowerInclusive: true},
+	expectSQL: `daterange('2020-02-02'::date, '2020-03-01'::date, '[)')`,
+}, {
+	name:      "unbounded range",
+	value:     Range[int]{LowerUnbounded: true, UpperUnbounded: true},
+	expectSQL: `int8range(NULL, NULL, '()')`,
+}, {
+	name:      "empty range",
+	value:     Range[int]{Empty: true},
+	expectSQL: `'empty'::int8range`,
+}, {
+	name:      "typed range",
+	value:     Range[string]{Lower: "a", Upper: "it's", Type: "textrange"},
+	expectSQL: `textrange('a', 'it''s', '()')`,
+}, {
+	name:      "nil range pointer",
+	value:     (*Range[int])(nil),
+	expectSQL: `NULL`,
+}, {
+	name: "multirange",
+	value: Multirange[int]{Ranges: []Range[int]{
+		{Lower: 1, Upper: 3, LowerInclusive: true},
+		{Lower: 5, UpperUnbounded: true, LowerInclusive: true},
+	}},
+	expectSQL: `int8multirange(int8range(1, 3, '[)'), int8range(5, NULL, '[)'))`,
+}, {
+	name:      "empty multirange",
+	value:     Multirange[time.Time]{},
+	expectSQL: `tstzmultirange()`,
+}, {
+	name:      "struct",
+	value:     testItem{Name: "it's", Supplier: 42, Price: 1.5},
+	expectSQL: `ROW('it''s', 42, 1.5)`,
+}, {
+	name:      "struct pointer",
+	value:     &testItem{Name: "a"},
+	expectSQL: `ROW('a', 0, 0)`,
+}, {
+	name:      "row",
+	value:     Row{Struct: testItem{Name: "a", Supplier: 42, Price: 1.5}, Type: "inventory_item"},
+	expectSQL: `ROW('a', 42, 1.5)::inventory_item`,
+}, {
+	name:      "row with fields",
+	value:     Row{Struct: &testItem{Name: "a", Supplier: 42, Price: 1.5}, Type: "inventory_item", Fields: []string{"price", "Name"}},
+	expectSQL: `ROW(1.5, 'a')::inventory_item`,
+}, {
+	name:      "nil row",
+	value:     Row{Struct: (*testItem)(nil), Type: "inventory_item"},
+	expectSQL: `NULL`,
+}, {
+	name:      "nested struct",
+	value:     Row{Struct: struct{ A, B testItem }{}},
+	expectSQL: `ROW(ROW('', 0, 0), ROW('', 0, 0))`,
+}, {
+	name:      "hstore",
+	value:     map[string]string{"b": `it's "quoted"`, "a": `back\slash`, "": ""},
+	expectSQL: `'""=>"", "a"=>"back\\slash", "b"=>"it''s \"quoted\""'::hstore`,
+}, {
+	name:      "hstore with nulls",
+	value:     map[string]*string{"a": nil, "b": newString("c")},
+	expectSQL: `'"a"=>NULL, "b"=>"c"'::hstore`,
+}, {
+	name:      "empty hstore",
+	value:     map[string]string{},
+	expectSQL: `''::hstore`,
+}, {
+	name:      "nil hstore",
+	value:     map[string]string(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "named hstore",
+	value:     testHstore{"a": "b"},
+	expectSQL: `'"a"=>"b"'::hstore`,
+}, {
+	name:      "empty hstore slice",
+	value:     []map[string]string{},
+	expectSQL: `ARRAY[]::hstore[]`,
 }}
 
+type testBytes []byte
+
+type testHstore map[string]testString
+
+type testItem struct {
+	Name     string
+	Supplier int     `sql:"supplier_id"`
+	Price    float64 `sql:"price,omitempty"`
+	Notes    string  `sql:"-"`
+	internal int
+}
+
+type testUUID [16]byte
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func mustParseMAC(s string) net.HardwareAddr {
+	a, err := net.ParseMAC(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+type testInt int
+
+type testUint16 uint16
+
+type testFloat32 float32
+
+type testString string
+
+type testBool bool
+
 func TestPostgresLiteral(t *testing.T) {
 	for _, test := range postgresLiteralTests {
 		t.Run(test.name, func(t *testing.T) {
@@ -188,6 +728,229 @@ func TestPostgresLiteralUnknown(t *testing.T) {
 	qt.Check(t, err, qt.ErrorMatches, `unknown type chan bool`)
 }
 
+func TestPostgresLiteralInvalidArray(t *testing.T) {
+	_, err := PostgresLiteral(Array{Elems: "abc", ElemType: "text"})
+	qt.Check(t, err, qt.ErrorMatches, `cannot use string as Array elements`)
+
+	_, err = PostgresLiteral([]interface{}{make(chan bool)})
+	qt.Check(t, err, qt.ErrorMatches, `unknown type chan bool`)
+}
+
+func TestPostgresLiteralInfiniteBigFloat(t *testing.T) {
+	_, err := PostgresLiteral(new(big.Float).SetInf(true))
+	qt.Check(t, err, qt.ErrorMatches, `cannot represent -Inf as numeric`)
+}
+
+func TestPostgresLiteralInvalidJSON(t *testing.T) {
+	_, err := PostgresLiteral(JSON{Data: make(chan bool)})
+	qt.Check(t, err, qt.ErrorMatches, `json: unsupported type: chan bool`)
+}
+
+func TestPostgresLiteralInvalidNetwork(t *testing.T) {
+	_, err := PostgresLiteral(net.IP{1, 2, 3})
+	qt.Check(t, err, qt.ErrorMatches, `invalid IP address \?010203`)
+
+	_, err = PostgresLiteral(net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(8, 32)})
+	qt.Check(t, err, qt.ErrorMatches, `invalid IP network 2001:db8:: with mask ff000000`)
+
+	_, err = PostgresLiteral(netip.MustParseAddr("fe80::1%eth0"))
+	qt.Check(t, err, qt.ErrorMatches, `cannot represent IP address fe80::1%eth0 with zone`)
+
+	_, err = PostgresLiteral(net.HardwareAddr{1, 2, 3})
+	qt.Check(t, err, qt.ErrorMatches, `invalid hardware address 01:02:03`)
+}
+
+func TestPostgresLiteralUnknownRangeType(t *testing.T) {
+	_, err := PostgresLiteral(Range[string]{Lower: "a", Upper: "b"})
+	qt.Check(t, err, qt.ErrorMatches, `cannot determine range type for string`)
+
+	_, err = PostgresLiteral(Multirange[string]{})
+	qt.Check(t, err, qt.ErrorMatches, `cannot determine multirange type for string`)
+}
+
+var invalidTypeNames = []string{
+	"int[]; DROP TABLE users; --",
+	"integer)",
+	"text OR true",
+	"\"quoted\"",
+	"public.",
+	".item",
+	"a.b.c",
+	"numeric(10",
+	"numeric(a)",
+	"numeric()",
+	"int[",
+	"int[x]",
+	"1int",
+	"double  precision",
+	"text with time zone",
+	"int/**/",
+	"int\n",
+}
+
+func TestPostgresLiteralInvalidTypeName(t *testing.T) {
+	for _, name := range invalidTypeNames {
+		t.Run(name, func(t *testing.T) {
+			for _, v := range []interface{}{
+				Array{Elems: []int{1}, ElemType: name},
+				Array{Elems: []int{}, ElemType: name},
+				Range[int]{Lower: 1, Upper: 2, Type: name},
+				Range[int]{Empty: true, Type: name},
+				Multirange[int]{Type: name},
+				Multirange[int]{Ranges: []Range[int]{{Lower: 1, Type: name}}},
+				Row{Struct: testItem{}, Type: name},
+				Row{Struct: (*testItem)(nil), Type: name},
+			} {
+				_, err := PostgresLiteral(v)
+				qt.Check(t, err, qt.ErrorMatches, `invalid SQL type name ".*"`, qt.Commentf("%#v", v))
+			}
+		})
+	}
+}
+
+func TestPostgresLiteralInvalidRow(t *testing.T) {
+	_, err := PostgresLiteral(Row{Struct: 1})
+	qt.Check(t, err, qt.ErrorMatches, `cannot use int as Row value`)
+
+	_, err = PostgresLiteral(Row{Struct: testItem{}, Fields: []string{"Notes"}})
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate.testItem has no field "Notes"`)
+
+	_, err = PostgresLiteral(struct {
+		C chan bool `sql:"c"`
+	}{})
+	qt.Check(t, err, qt.ErrorMatches, `unknown type chan bool`)
+
+	for _, v := range []interface{}{struct{ a int }{}, struct{ C chan bool }{}, url.Userinfo{}, regexp.Regexp{}} {
+		_, err = PostgresLiteral(v)
+		qt.Check(t, err, qt.ErrorMatches, `unknown type .*`)
+	}
+
+	_, err = PostgresLiteral(Row{Struct: struct{ a int }{}})
+	qt.Check(t, err, qt.ErrorMatches, `struct \{ a int \} has no fields that can be formatted`)
+
+	_, err = PostgresLiteral(Row{Struct: &testItem{}, Fields: []string{}})
+	qt.Assert(t, err, qt.IsNil)
+
+	_, err = PostgresLiteral(Row{Struct: struct {
+		A int `sql:"-"`
+	}{}})
+	qt.Check(t, err, qt.ErrorMatches, `struct \{ A int "sql:\\"-\\"" \} has no fields that can be formatted`)
+}
+
+type testCycle struct {
+	Next *testCycle `sql:"next"`
+}
+
+func TestPostgresLiteralCycle(t *testing.T) {
+	var c testCycle
+	c.Next = &c
+	_, err := PostgresLiteral(c)
+	qt.Check(t, err, qt.ErrorMatches, `cannot format .* nested more than 100 levels deep, the value might contain a cycle`)
+
+	_, err = PostgresLiteral(Row{Struct: &c})
+	qt.Check(t, err, qt.ErrorMatches, `.*the value might contain a cycle`)
+
+	s := []interface{}{nil}
+	s[0] = s
+	_, err = PostgresLiteral(s)
+	qt.Check(t, err, qt.ErrorMatches, `.*the value might contain a cycle`)
+
+	_, err = PostgresLiteral(testCycle{Next: &testCycle{}})
+	qt.Check(t, err, qt.IsNil)
+}
+
+func TestPostgresLiteralInvalidMap(t *testing.T) {
+	_, err := PostgresLiteral(map[string]int{"a": 1})
+	qt.Check(t, err, qt.ErrorMatches, `unknown type map\[string\]int`)
+
+	_, err = PostgresLiteral(map[int]string{1: "a"})
+	qt.Check(t, err, qt.ErrorMatches, `unknown type map\[int\]string`)
+}
+
+var postgresTimeZonesTests = []struct {
+	name        string
+	timeZones   PostgresTimeZones
+	value       interface{}
+	expectSQL   string
+	expectError string
+}{{
+	name:      "keep",
+	timeZones: PostgresTimeZonesKeep,
+	value:     time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60)),
+	expectSQL: `'2020-02-02T12:30:45-03:00'`,
+}, {
+	name:      "utc",
+	timeZones: PostgresTimeZonesUTC,
+	value:     time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60)),
+	expectSQL: `'2020-02-02T15:30:45Z'`,
+}, {
+	name:      "utc timestamptz",
+	timeZones: PostgresTimeZonesUTC,
+	value:     TimestampTZ(time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60))),
+	expectSQL: `'2020-02-02T15:30:45Z'::timestamptz`,
+}, {
+	name:        "error",
+	timeZones:   PostgresTimeZonesError,
+	value:       time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60)),
+	expectError: `time 2020-02-02 12:30:45 -0300 UTC-3 is not in UTC`,
+}, {
+	name:        "error timestamptz",
+	timeZones:   PostgresTimeZonesError,
+	value:       TimestampTZ(time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60))),
+	expectError: `time 2020-02-02 12:30:45 -0300 UTC-3 is not in UTC`,
+}, {
+	name:      "error utc",
+	timeZones: PostgresTimeZonesError,
+	value:     time.Date(2020, time.February, 2, 12, 30, 45, 0, time.UTC),
+	expectSQL: `'2020-02-02T12:30:45Z'`,
+}, {
+	name:      "error timestamp",
+	timeZones: PostgresTimeZonesError,
+	value:     Timestamp(time.Date(2020, time.February, 2, 12, 30, 45, 0, time.FixedZone("UTC-3", -3*60*60))),
+	expectSQL: `'2020-02-02 12:30:45'::timestamp`,
+}}
+
+func TestPostgresTimeZones(t *testing.T) {
+	for _, test := range postgresTimeZonesTests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := Postgres{TimeZones: test.timeZones}.Literal(test.value)
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, RawSQL(test.expectSQL))
+		})
+	}
+}
+
+func TestPostgresDialect(t *testing.T) {
+	var d Dialect = Postgres{}
+	qt.Check(t, d.Name(), qt.Equals, "postgres")
+	id, err := d.QuoteIdentifier(`a "b"`)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, id, qt.Equals, RawSQL(`"a ""b"""`))
+	b, err := d.Bool(true)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, b, qt.Equals, RawSQL("TRUE"))
+	qt.Check(t, d.Null(), qt.Equals, RawSQL("NULL"))
+	qt.Check(t, d.Placeholders(), qt.Equals, DollarPlaceholders)
+	qt.Check(t, d.Supports(FeatureBooleans), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureArrays), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureDollarQuoting), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureBackslashEscapes), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureEscapeStrings), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureHashComments), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureNestedComments), qt.IsTrue)
+	qt.Check(t, d.Supports(FeatureDashCommentSpace), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureQQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureTripleQuoting), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashQuotes), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBackslashIdentifiers), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBacktickIdentifiers), qt.IsFalse)
+	qt.Check(t, d.Supports(FeatureBracketIdentifiers), qt.IsFalse)
+}
+
 func newBool(b bool) *bool {
 	return &b
 }
@@ -204,6 +967,14 @@ func newInt64(i int64) *int64 {
 	return &i
 }
 
+func newInt32(i int32) *int32 {
+	return &i
+}
+
+func newUint(i uint) *uint {
+	return &i
+}
+
 func newString(s string) *string {
 	return &s
 }
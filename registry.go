@@ -0,0 +1,149 @@
+package sqltemplate
+
+import "reflect"
+
+// An Encoder formats a value as an SQL literal.
+type Encoder func(v interface{}) (RawSQL, error)
+
+// A Registry holds encoders for types that a Dialect would otherwise be
+// unable to format, or would format incorrectly. This allows types from
+// other packages to be formatted without having to wrap them. The zero
+// value is an empty Registry ready to use.
+//
+// Encoders must be registered before the Registry is used by a Dialect.
+type Registry struct {
+	types  map[reflect.Type]Encoder
+	ifaces []registeredInterface
+	kinds  map[reflect.Kind]Encoder
+}
+
+type registeredInterface struct {
+	t reflect.Type
+	e Encoder
+}
+
+// RegisterEncoder registers e as the encoder for values of type t. If t
+// is an interface type then e is used for all values that implement t
+// and that do not have an encoder registered for their own type.
+// Interface encoders are tried in the order they are registered.
+func (r *Registry) RegisterEncoder(t reflect.Type, e Encoder) {
+	if t.Kind() == reflect.Interface {
+		r.ifaces = append(r.ifaces, registeredInterface{t: t, e: e})
+		return
+	}
+	if r.types == nil {
+		r.types = make(map[reflect.Type]Encoder)
+	}
+	r.types[t] = e
+}
+
+// RegisterKindEncoder registers e as the encoder for values of named
+// types with the underlying kind k, that have no other encoder
+// registered. For example registering an encoder for reflect.String
+// applies to all named string types, but not to string itself. Types
+// defined in this package, such as RawSQL and Identifier, are not
+// affected by kind encoders.
+func (r *Registry) RegisterKindEncoder(k reflect.Kind, e Encoder) {
+	if r.kinds == nil {
+		r.kinds = make(map[reflect.Kind]Encoder)
+	}
+	r.kinds[k] = e
+}
+
+// encode formats v using the encoder registered for its type. If v is a
+// pointer then the values it points to are also considered, with an
+// encoder for the type of any of the values taking precedence over an
+// interface encoder, which in turn takes precedence over a kind encoder.
+// Nil pointers are never passed to an encoder. If no encoder is found
+// then ok is false.
+func (r *Registry) encode(v interface{}) (_ RawSQL, ok bool, err error) {
+	if r == nil || v == nil {
+		return "", false, nil
+	}
+	var values []reflect.Value
+	for rv := reflect.ValueOf(v); ; rv = rv.Elem() {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "", false, nil
+		}
+		values = append(values, rv)
+		if rv.Kind() != reflect.Ptr {
+			break
+		}
+	}
+	for _, lookup := range []func(reflect.Type) Encoder{r.lookupType, r.lookupInterface, r.lookupKind} {
+		for _, rv := range values {
+			if e := lookup(rv.Type()); e != nil {
+				s, err := e(rv.Interface())
+				return s, true, err
+			}
+		}
+	}
+	return "", false, nil
+}
+
+func (r *Registry) lookupType(t reflect.Type) Encoder {
+	return r.types[t]
+}
+
+func (r *Registry) lookupInterface(t reflect.Type) Encoder {
+	for _, i := range r.ifaces {
+		if t.Implements(i.t) {
+			return i.e
+		}
+	}
+	return nil
+}
+
+func (r *Registry) lookupKind(t reflect.Type) Encoder {
+	if p := t.PkgPath(); p == "" || p == packagePath {
+		return nil
+	}
+	return r.kinds[t.Kind()]
+}
+
+// packagePath is the import path of this package.
+var packagePath = reflect.TypeOf(RawSQL("")).PkgPath()
+
+// usedBy reports whether an encoder in r would be used to format rv, or
+// any value contained in it, depth is the number of values that contain
+// rv. The exported fields of all structs are considered, as are the
+// elements of slices, arrays and maps.
+func (r *Registry) usedBy(rv reflect.Value, depth int) bool {
+	if r == nil || !rv.IsValid() || depth >= postgresMaxDepth {
+		return false
+	}
+	if rv.Kind() != reflect.Interface && (rv.Kind() != reflect.Ptr || !rv.IsNil()) {
+		t := rv.Type()
+		if r.lookupType(t) != nil || r.lookupInterface(t) != nil || r.lookupKind(t) != nil {
+			return true
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return false
+		}
+		return r.usedBy(rv.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if r.usedBy(rv.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			if r.usedBy(iter.Value(), depth+1) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" && r.usedBy(rv.Field(i), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package sqltemplate
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type testDecimal struct {
+	s string
+}
+
+func (d testDecimal) String() string {
+	return d.s
+}
+
+func newTestRegistry() *Registry {
+	var r Registry
+	r.RegisterEncoder(reflect.TypeOf(testDecimal{}), func(v interface{}) (RawSQL, error) {
+		return RawSQL(v.(testDecimal).s + "::numeric"), nil
+	})
+	r.RegisterEncoder(reflect.TypeOf((*fmt.Stringer)(nil)).Elem(), func(v interface{}) (RawSQL, error) {
+		return PostgresLiteral(v.(fmt.Stringer).String())
+	})
+	r.RegisterKindEncoder(reflect.Int, func(v interface{}) (RawSQL, error) {
+		return PostgresLiteral(fmt.Sprint(v))
+	})
+	r.RegisterEncoder(reflect.TypeOf(testBool(false)), func(v interface{}) (RawSQL, error) {
+		return "", errors.New("test error")
+	})
+	return &r
+}
+
+var registryTests = []struct {
+	name        string
+	value       interface{}
+	expectSQL   RawSQL
+	expectError string
+}{{
+	name:      "type",
+	value:     testDecimal{s: "1.5"},
+	expectSQL: `1.5::numeric`,
+}, {
+	name:      "pointer",
+	value:     &testDecimal{s: "1.5"},
+	expectSQL: `1.5::numeric`,
+}, {
+	name:      "nil pointer",
+	value:     (*testDecimal)(nil),
+	expectSQL: `NULL`,
+}, {
+	name:      "interface",
+	value:     &strings.Builder{},
+	expectSQL: `''`,
+}, {
+	// ast.ChanDir is a named int from another package that does not
+	// implement fmt.Stringer, so only the kind encoder applies.
+	name:      "kind",
+	value:     ast.RECV,
+	expectSQL: `'2'`,
+}, {
+	name:      "interface before kind",
+	value:     time.March,
+	expectSQL: `'March'`,
+}, {
+	name:      "unnamed kind",
+	value:     3,
+	expectSQL: `3`,
+}, {
+	name:      "package kind",
+	value:     testInt(1),
+	expectSQL: `1`,
+}, {
+	name:      "identifier",
+	value:     Identifier("a"),
+	expectSQL: `"a"`,
+}, {
+	name:      "raw sql",
+	value:     RawSQL("a"),
+	expectSQL: `a`,
+}, {
+	name:      "array elements",
+	value:     []interface{}{testDecimal{s: "1"}, ast.SEND, 1},
+	expectSQL: `ARRAY[1::numeric, '1', 1]`,
+}, {
+	name:        "error",
+	value:       testBool(true),
+	expectError: `test error`,
+}, {
+	name:      "unregistered",
+	value:     1,
+	expectSQL: `1`,
+}}
+
+func TestRegistry(t *testing.T) {
+	qt.Assert(t, reflect.TypeOf(ast.RECV).Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()), qt.IsFalse)
+	d := Postgres{Registry: newTestRegistry()}
+	for _, test := range registryTests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := d.Literal(test.value)
+			if test.expectError != "" {
+				qt.Check(t, err, qt.ErrorMatches, test.expectError)
+				return
+			}
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, test.expectSQL)
+		})
+	}
+}
+
+func TestRegistryInTemplate(t *testing.T) {
+	tmpl, err := New("").WithDialect(Postgres{Registry: newTestRegistry()}).Parse(`SELECT {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, testDecimal{s: "2.25"})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT 2.25::numeric`)
+}
+
+func TestRegistryExecuteArgs(t *testing.T) {
+	tmpl, err := New("").WithDialect(Postgres{Registry: newTestRegistry()}).Parse(`SELECT {{.A}}, {{.B}}, {{.C}}, {{.D}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	query, args, err := tmpl.ExecuteArgs(map[string]interface{}{
+		"A": testDecimal{s: "2.25"},
+		"B": []testDecimal{{s: "1.5"}},
+		"C": "x",
+		"D": []string{"y"},
+	})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `SELECT 2.25::numeric, ARRAY[1.5::numeric], $1, $2`)
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"x", Array{Elems: []string{"y"}}})
+}
@@ -0,0 +1,131 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLite is the Dialect for the SQLite database.
+type SQLite struct{}
+
+// Name implements Dialect.
+func (SQLite) Name() string {
+	return "sqlite"
+}
+
+// QuoteIdentifier implements Dialect, see
+// https://www.sqlite.org/lang_keywords.html.
+func (SQLite) QuoteIdentifier(name string) (RawSQL, error) {
+	return RawSQL(`"` + strings.ReplaceAll(name, `"`, `""`) + `"`), nil
+}
+
+// Bool implements Dialect. SQLite stores booleans as the integers 1 and
+// 0.
+func (SQLite) Bool(b bool) (RawSQL, error) {
+	if b {
+		return RawSQL("1"), nil
+	}
+	return RawSQL("0"), nil
+}
+
+// Null implements Dialect.
+func (SQLite) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, SQLite drivers use
+// QuestionPlaceholders.
+func (SQLite) Placeholders() PlaceholderStyle {
+	return QuestionPlaceholders
+}
+
+// Supports implements Dialect. SQLite supports
+// FeatureBacktickIdentifiers and FeatureBracketIdentifiers.
+func (SQLite) Supports(f Feature) bool {
+	switch f {
+	case FeatureBacktickIdentifiers, FeatureBracketIdentifiers:
+		return true
+	}
+	return false
+}
+
+// sqliteTimeFormat is the format used for time literals. This is a format
+// understood by SQLite's date and time functions, see
+// https://www.sqlite.org/lang_datefunc.html.
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// SQLiteLiteral formats the value v as a literal suitable for use in
+// queries used with the SQLite database. It is equivalent to calling the
+// Literal method of a SQLite value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  Either 1, or 0.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  The %g encoding provided by fmt.Printf. SQLite cannot represent
+//	  +Inf, -Inf or NaN as literals so these values result in an error.
+//	string
+//	  A string literal.
+//	[]byte
+//	  A BLOB literal, see https://www.sqlite.org/lang_expr.html#litvalue.
+//	time.Time
+//	  A string literal containing the time in the form
+//	  "YYYY-MM-DD HH:MM:SS.SSSSSSSSS+HH:MM".
+//	Identifier
+//	  A quoted identifier, see https://www.sqlite.org/lang_keywords.html.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func SQLiteLiteral(v interface{}) (RawSQL, error) {
+	return SQLite{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// SQLiteLiteral.
+func (d SQLite) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if v1 == nil {
+			return d.Null(), nil
+		}
+		return RawSQL(fmt.Sprintf("X'%X'", v1)), nil
+	case float64:
+		if math.IsInf(v1, 0) || math.IsNaN(v1) {
+			return "", fmt.Errorf("cannot represent %v in SQLite", v1)
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		return RawSQL(`'` + strings.ReplaceAll(v1, `'`, `''`) + `'`), nil
+	case time.Time:
+		return RawSQL(`'` + v1.Format(sqliteTimeFormat) + `'`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
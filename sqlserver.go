@@ -0,0 +1,131 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLServer is the Dialect for the Microsoft SQL Server database.
+type SQLServer struct {
+	// Location is the time zone time.Time values are converted to
+	// before formatting. If this is nil then UTC is used.
+	Location *time.Location
+}
+
+// Name implements Dialect.
+func (SQLServer) Name() string {
+	return "sqlserver"
+}
+
+// QuoteIdentifier implements Dialect. Identifiers are quoted using
+// square brackets, see
+// https://learn.microsoft.com/en-us/sql/relational-databases/databases/database-identifiers.
+func (SQLServer) QuoteIdentifier(name string) (RawSQL, error) {
+	return RawSQL(`[` + strings.ReplaceAll(name, `]`, `]]`) + `]`), nil
+}
+
+// Bool implements Dialect. SQL Server represents booleans as the bit
+// values 1 and 0.
+func (SQLServer) Bool(b bool) (RawSQL, error) {
+	if b {
+		return RawSQL("1"), nil
+	}
+	return RawSQL("0"), nil
+}
+
+// Null implements Dialect.
+func (SQLServer) Null() RawSQL {
+	return RawSQL("NULL")
+}
+
+// Placeholders implements Dialect, SQL Server drivers use
+// AtPPlaceholders.
+func (SQLServer) Placeholders() PlaceholderStyle {
+	return AtPPlaceholders
+}
+
+// Supports implements Dialect. SQL Server supports
+// FeatureNestedComments.
+func (SQLServer) Supports(f Feature) bool {
+	return f == FeatureNestedComments
+}
+
+// SQLServerLiteral formats the value v as a literal suitable for use in
+// queries used with the Microsoft SQL Server database. It is equivalent
+// to calling the Literal method of a zero SQLServer value.
+//
+// If v implements SQLLiteraler then the result of SQLLiteral() is used.
+// Otherwise, if v implements database/sql/driver.Valuer then Value() will
+// be called before further processing.
+//
+// The literal form used for values of a specified type is:
+//
+//	nil
+//	  The SQL keyword NULL.
+//	bool
+//	  Either 1, or 0.
+//	int, int64
+//	  The decimal value.
+//	float64
+//	  The %g encoding provided by fmt.Printf. SQL Server cannot
+//	  represent +Inf, -Inf or NaN so these values result in an error.
+//	string
+//	  A Unicode string literal, for example N'text'.
+//	[]byte
+//	  A binary literal, for example 0x74657374.
+//	time.Time
+//	  A string literal containing the date and time, in Location, in a
+//	  form compatible with the datetime2 type.
+//	Identifier
+//	  A delimited identifier in square brackets.
+//
+// Pointers to any of these types are also supported, a nil pointer is
+// formatted as NULL.
+func SQLServerLiteral(v interface{}) (RawSQL, error) {
+	return SQLServer{}.Literal(v)
+}
+
+// Literal implements Dialect by formatting v as described in
+// SQLServerLiteral.
+func (d SQLServer) Literal(v interface{}) (RawSQL, error) {
+	v, err := indirect(d, v)
+	if err != nil {
+		return "", err
+	}
+	switch v1 := v.(type) {
+	case nil:
+		return d.Null(), nil
+	case RawSQL:
+		return v1, nil
+	case Identifier:
+		return d.QuoteIdentifier(string(v1))
+	case bool:
+		return d.Bool(v1)
+	case []byte:
+		if v1 == nil {
+			return d.Null(), nil
+		}
+		return RawSQL(fmt.Sprintf("0x%X", v1)), nil
+	case float64:
+		if math.IsInf(v1, 0) || math.IsNaN(v1) {
+			return "", fmt.Errorf("cannot represent %v in SQL Server", v1)
+		}
+		return RawSQL(strconv.FormatFloat(v1, 'g', -1, 64)), nil
+	case int:
+		return RawSQL(strconv.Itoa(v1)), nil
+	case int64:
+		return RawSQL(strconv.FormatInt(v1, 10)), nil
+	case string:
+		return RawSQL(`N'` + strings.ReplaceAll(v1, `'`, `''`) + `'`), nil
+	case time.Time:
+		loc := d.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		return RawSQL(`'` + v1.In(loc).Format("2006-01-02T15:04:05.9999999") + `'`), nil
+	}
+	return "", fmt.Errorf("unknown type %T", v)
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/fs"
 	"path/filepath"
+	"strings"
 	"text/template"
 	"text/template/parse"
 )
@@ -13,10 +14,6 @@ import (
 // https://golang.org/pkg/text/template#FuncMap for details.
 type FuncMap = template.FuncMap
 
-var funcs = FuncMap{
-	"sqlliteral": PostgresLiteral,
-}
-
 // Must is a helper that wraps a call to a function returning (*Template, error)
 // and panics if the error is non-nil. It is intended for use in variable
 // initializations such as
@@ -88,18 +85,103 @@ func ParseGlob(pattern string) (*Template, error) {
 // A Template is the representation of a parsed template.
 type Template struct {
 	text *template.Template
+	ns   *nameSpace
+}
+
+// A nameSpace holds the state shared by a set of associated templates.
+type nameSpace struct {
+	// dialect is the SQL dialect the templates produce.
+	dialect Dialect
+
+	// literal is the function currently registered as sqlliteral.
+	literal func(interface{}) (RawSQL, error)
+
+	// placeholders is the placeholder style used by ExecuteArgs.
+	placeholders PlaceholderStyle
+
+	// reuseArgs is set if ExecuteArgs should use a single placeholder
+	// for equal argument values.
+	reuseArgs bool
+
+	// emptyIn determines the behaviour of the in function when given
+	// an empty list.
+	emptyIn emptyInMode
+
+	// overridden holds the names of built-in functions that have been
+	// replaced using Funcs.
+	overridden map[string]bool
+
+	// contextual is set if pipelines are escaped according to the
+	// SQL text surrounding them.
+	contextual bool
+
+	// trusted holds the names of functions that have been added using
+	// TrustedFuncs.
+	trusted map[string]bool
+
+	// denyRawSQL is set if RawSQL values are not allowed in template
+	// data.
+	denyRawSQL bool
+
+	// escapeErr is set if escaping a template failed. Templates in the
+	// name space cannot be executed once this is set.
+	escapeErr error
+}
+
+func newNameSpace() *nameSpace {
+	ns := new(nameSpace)
+	ns.setDialect(Postgres{})
+	return ns
+}
+
+// setDialect sets the dialect of the name space along with the values
+// that are derived from it.
+func (ns *nameSpace) setDialect(d Dialect) {
+	ns.dialect = d
+	ns.literal = d.Literal
+	ns.placeholders = d.Placeholders()
+}
+
+// funcs returns the built-in functions the name space adds to its
+// templates, using the given function as sqlliteral. Functions that have
+// been overridden are not included.
+func (ns *nameSpace) funcs(literal func(interface{}) (RawSQL, error)) FuncMap {
+	fm := FuncMap{
+		"sqlliteral": literal,
+		"in": func(v interface{}) (RawSQL, error) {
+			return ns.in(literal, v)
+		},
+		"json": jsonFunc,
+
+		stringEscaper:          escapeString,
+		backslashStringEscaper: escapeBackslashString,
+		identEscaper:           escapeIdentifier,
+		backslashIdentEscaper:  escapeBackslashIdentifier,
+		minusSpacer:            spaceMinus,
+		rawSQLChecker:          checkRawSQL,
+	}
+	for name := range ns.overridden {
+		delete(fm, name)
+	}
+	return fm
 }
 
 func (t *Template) init() {
+	if t.ns == nil {
+		t.ns = newNameSpace()
+	}
 	if t.text == nil {
-		t.text = new(template.Template).Funcs(funcs)
+		t.text = new(template.Template).Funcs(t.ns.funcs(t.ns.literal))
 	}
 }
 
-// New allocates a new, undefined template with the given name.
+// New allocates a new, undefined template with the given name. The
+// template uses the Postgres dialect.
 func New(name string) *Template {
+	ns := newNameSpace()
 	return &Template{
-		text: template.New(name).Funcs(funcs),
+		text: template.New(name).Funcs(ns.funcs(ns.literal)),
+		ns:   ns,
 	}
 }
 
@@ -110,8 +192,18 @@ func New(name string) *Template {
 // created, defined, and returned.
 func (t *Template) AddParseTree(name string, tree *parse.Tree) (*Template, error) {
 	t.init()
-	_, err := t.text.AddParseTree(name, escapeTree(tree.Copy()))
-	return t, err
+	tree = tree.Copy()
+	if _, err := t.ns.escapeTree(tree); err != nil {
+		return nil, err
+	}
+	if _, err := t.text.AddParseTree(name, tree); err != nil {
+		return t, err
+	}
+	if err := t.ns.checkCalls(t.text); err != nil {
+		t.ns.escapeErr = err
+		return nil, err
+	}
+	return t, nil
 }
 
 // Clone returns a duplicate of the template, including all associated
@@ -129,6 +221,22 @@ func (t *Template) Clone() (*Template, error) {
 			return nil, err
 		}
 	}
+	if t.ns != nil {
+		ns := *t.ns
+		ns.overridden = make(map[string]bool, len(t.ns.overridden))
+		for name := range t.ns.overridden {
+			ns.overridden[name] = true
+		}
+		ns.trusted = make(map[string]bool, len(t.ns.trusted))
+		for name := range t.ns.trusted {
+			ns.trusted[name] = true
+		}
+		t1.ns = &ns
+		if t1.text != nil {
+			// Rebind the built-in functions to the new name space.
+			t1.text.Funcs(t1.ns.funcs(t1.ns.literal))
+		}
+	}
 	return &t1, nil
 }
 
@@ -151,6 +259,12 @@ func (t *Template) Delims(left, right string) *Template {
 	return t
 }
 
+// Dialect returns the SQL dialect used by the template.
+func (t *Template) Dialect() Dialect {
+	t.init()
+	return t.ns.dialect
+}
+
 // Execute applies a parsed template to the specified data object, and
 // writes the output to w. If an error occurs executing the template or
 // writing its output, execution stops, but partial results may already
@@ -164,9 +278,47 @@ func (t *Template) Execute(w io.Writer, data interface{}) error {
 	if t.text == nil {
 		return fmt.Errorf("sqltemplate: %q is an incomplete or empty template", t.Name())
 	}
+	if t.ns.escapeErr != nil {
+		return t.ns.escapeErr
+	}
 	return t.text.Execute(w, data)
 }
 
+// ExecuteArgs applies a parsed template to the specified data object and
+// returns the resulting query along with the arguments to be passed with
+// it, for example to database/sql's DB.Query. Rather than being formatted
+// with sqlliteral the result of each pipeline is added to args and
+// replaced in the query with a placeholder, see Placeholders. RawSQL and
+// Identifier values are not valid arguments and are still formatted using
+// sqlliteral.
+//
+// Each call to ExecuteArgs operates on a copy of the template, so the
+// template may be executed safely in parallel.
+func (t *Template) ExecuteArgs(data interface{}) (query string, args []interface{}, err error) {
+	if t.text == nil {
+		return "", nil, fmt.Errorf("sqltemplate: %q is an incomplete or empty template", t.Name())
+	}
+	if t.ns.escapeErr != nil {
+		return "", nil, t.ns.escapeErr
+	}
+	tt, err := t.text.Clone()
+	if err != nil {
+		return "", nil, err
+	}
+	al := argList{
+		format:  t.ns.literal,
+		dialect: t.ns.dialect,
+		style:   t.ns.placeholders,
+		reuse:   t.ns.reuseArgs,
+	}
+	tt.Funcs(t.ns.funcs(al.literal))
+	var sb strings.Builder
+	if err := tt.Execute(&sb, data); err != nil {
+		return "", nil, err
+	}
+	return sb.String(), al.args, nil
+}
+
 // ExecuteTemplate applies the template associated with t that has the
 // given name to the specified data object and writes the output to w. If
 // an error occurs executing the template or writing its output, execution
@@ -187,9 +339,50 @@ func (t *Template) ExecuteTemplate(w io.Writer, name string, data interface{}) e
 // the name cannot be used syntactically as a function in a template. It is
 // legal to overwrite elements of the map. The return value is the
 // template, so calls can be chained.
+//
+// Functions added with Funcs are not trusted to produce SQL. If such a
+// function returns a RawSQL value then execution stops with an error.
+// Functions that produce SQL must be added with TrustedFuncs. The
+// sqlliteral function is always trusted.
 func (t *Template) Funcs(funcMap FuncMap) *Template {
+	return t.addFuncs(funcMap, false)
+}
+
+func (t *Template) addFuncs(funcMap FuncMap, trusted bool) *Template {
 	t.init()
-	t.text.Funcs(funcMap)
+	fm := make(FuncMap, len(funcMap))
+	for name, fn := range funcMap {
+		if !trusted && name != "sqlliteral" {
+			fn = untrustedFunc(name, fn)
+		}
+		fm[name] = fn
+	}
+	t.text.Funcs(fm)
+	builtins := t.ns.funcs(nil)
+	for name := range funcMap {
+		if name == "sqlliteral" {
+			continue
+		}
+		if _, ok := builtins[name]; ok {
+			if t.ns.overridden == nil {
+				t.ns.overridden = make(map[string]bool)
+			}
+			t.ns.overridden[name] = true
+		}
+		if trusted {
+			if t.ns.trusted == nil {
+				t.ns.trusted = make(map[string]bool)
+			}
+			t.ns.trusted[name] = true
+		} else {
+			delete(t.ns.trusted, name)
+		}
+	}
+	if f, ok := funcMap["sqlliteral"].(func(interface{}) (RawSQL, error)); ok {
+		// Rebind the built-in functions that use sqlliteral.
+		t.ns.literal = f
+		t.text.Funcs(t.ns.funcs(f))
+	}
 	return t
 }
 
@@ -206,6 +399,7 @@ func (t *Template) Lookup(name string) *Template {
 	}
 	return &Template{
 		text: tt,
+		ns:   t.ns,
 	}
 }
 
@@ -228,6 +422,7 @@ func (t *Template) New(name string) *Template {
 	t.init()
 	return &Template{
 		text: t.text.New(name),
+		ns:   t.ns,
 	}
 }
 
@@ -236,14 +431,120 @@ func (t *Template) New(name string) *Template {
 // sign in an option string. If the option string is unrecognized or
 // otherwise invalid, Option panics.
 //
-// This package does not define any options, the only options supported are
-// those listed in https://golang.org/pkg/text/template#Template.Option.
+// In addition to the options listed in
+// https://golang.org/pkg/text/template#Template.Option the following
+// options are supported:
+//
+//	emptyin=error
+//		The default. The in function returns an error when given an
+//		empty list.
+//	emptyin=null
+//		The in function returns (NULL) when given an empty list.
+//	escape=literal
+//		The default. The result of every pipeline is formatted using
+//		sqlliteral, and pipelines inside string literals or quoted
+//		identifiers are an error.
+//	escape=contextual
+//		Pipelines are escaped according to where they appear in the
+//		SQL text, see the package documentation for details. This
+//		option must be set before the template is parsed, changing it
+//		afterwards panics.
+//	placeholder=dollar
+//		ExecuteArgs uses DollarPlaceholders.
+//	placeholder=question
+//		ExecuteArgs uses QuestionPlaceholders.
+//	placeholder=atp
+//		ExecuteArgs uses AtPPlaceholders.
+//	placeholder=colon
+//		ExecuteArgs uses ColonPlaceholders.
+//	rawsql=allow
+//		The default. RawSQL values in template data are written to the
+//		output verbatim.
+//	rawsql=deny
+//		Strict mode. Template data must not contain RawSQL values,
+//		any that are formatted, either directly or as part of another
+//		value, cause an error. Only RawSQL values produced by trusted
+//		functions, see TrustedFuncs, are allowed. This option must be
+//		set before the template is parsed, changing it afterwards
+//		panics.
+//	reuseargs=false
+//		The default. ExecuteArgs adds a new argument for every value.
+//	reuseargs=true
+//		ExecuteArgs adds a single argument for comparable values that
+//		are equal, and refers to it with the same placeholder each time
+//		it is used. This has no effect if the placeholder style is not
+//		reusable. Values are compared with ==, after any conversion by
+//		the dialect, so unrelated values that happen to be equal, such
+//		as two fields that both hold 0, share a placeholder even if they
+//		are not the same object. Some databases deduce a single type
+//		for each placeholder, so a shared placeholder used where
+//		different types are expected, for example int and bigint
+//		columns, can cause an error.
 func (t *Template) Option(opt ...string) *Template {
 	t.init()
-	t.text.Option(opt...)
+	for _, o := range opt {
+		t.setOption(o)
+	}
 	return t
 }
 
+func (t *Template) setOption(opt string) {
+	if i := strings.Index(opt, "="); i >= 0 {
+		key, value := opt[:i], opt[i+1:]
+		switch key {
+		case "placeholder":
+			if style, ok := placeholderStyles[value]; ok {
+				t.ns.placeholders = style
+				return
+			}
+			panic("unrecognized option: " + opt)
+		case "emptyin":
+			switch value {
+			case "error":
+				t.ns.emptyIn = emptyInError
+				return
+			case "null":
+				t.ns.emptyIn = emptyInNull
+				return
+			}
+			panic("unrecognized option: " + opt)
+		case "escape":
+			switch value {
+			case "literal", "contextual":
+				contextual := value == "contextual"
+				if contextual != t.ns.contextual && t.parsed() {
+					panic("sqltemplate: option " + opt + " must be set before templates are parsed")
+				}
+				t.ns.contextual = contextual
+				return
+			}
+			panic("unrecognized option: " + opt)
+		case "rawsql":
+			switch value {
+			case "allow", "deny":
+				deny := value == "deny"
+				if deny != t.ns.denyRawSQL && t.parsed() {
+					panic("sqltemplate: option " + opt + " must be set before templates are parsed")
+				}
+				t.ns.denyRawSQL = deny
+				return
+			}
+			panic("unrecognized option: " + opt)
+		case "reuseargs":
+			switch value {
+			case "false":
+				t.ns.reuseArgs = false
+				return
+			case "true":
+				t.ns.reuseArgs = true
+				return
+			}
+			panic("unrecognized option: " + opt)
+		}
+	}
+	t.text.Option(opt)
+}
+
 // Parse parses text as a template body for t. Named template definitions
 // ({{define ...}} or {{block ...}} statements) in text define additional
 // templates associated with t and are removed from the definition of t
@@ -260,7 +561,9 @@ func (t *Template) Parse(text string) (*Template, error) {
 	if err != nil {
 		return nil, err
 	}
-	escapeTemplate(tt)
+	if err := t.ns.escapeTemplate(tt); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -274,7 +577,9 @@ func (t *Template) ParseFS(fsys fs.FS, patterns ...string) (*Template, error) {
 	if err != nil {
 		return nil, err
 	}
-	escapeTemplate(tt)
+	if err := t.ns.escapeTemplate(tt); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -295,7 +600,9 @@ func (t *Template) ParseFiles(filenames ...string) (*Template, error) {
 	if err != nil {
 		return nil, err
 	}
-	escapeTemplate(tt)
+	if err := t.ns.escapeTemplate(tt); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -313,10 +620,39 @@ func (t *Template) ParseGlob(pattern string) (*Template, error) {
 	if err != nil {
 		return nil, err
 	}
-	escapeTemplate(tt)
+	if err := t.ns.escapeTemplate(tt); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
+// parsed reports whether any templates associated with t have been
+// parsed.
+func (t *Template) parsed() bool {
+	return len(t.text.Templates()) > 0
+}
+
+// Placeholders sets the placeholder style used when the template is
+// executed with ExecuteArgs. The default style is the one returned by the
+// Placeholders method of the template's Dialect. The return value is the
+// template, so calls can be chained.
+func (t *Template) Placeholders(style PlaceholderStyle) *Template {
+	t.init()
+	t.ns.placeholders = style
+	return t
+}
+
+// TrustedFuncs is like Funcs, but the functions are trusted to produce
+// SQL. RawSQL values returned by trusted functions are written to the
+// output verbatim, so trusted functions must never build RawSQL values
+// from untrusted input. When RawSQL values are not allowed in template
+// data, see the "rawsql=deny" option, the result of a pipeline that ends
+// with a call to a trusted function is not checked. The return value is
+// the template, so calls can be chained.
+func (t *Template) TrustedFuncs(funcMap FuncMap) *Template {
+	return t.addFuncs(funcMap, true)
+}
+
 // Templates returns a slice of defined templates associated with t.
 func (t *Template) Templates() []*Template {
 	t.init()
@@ -325,14 +661,28 @@ func (t *Template) Templates() []*Template {
 	for i, tt := range tts {
 		ts[i] = &Template{
 			text: tt,
+			ns:   t.ns,
 		}
 	}
 	return ts
 }
 
-// escapeTemplate escapes all the templates defined in a template.
-func escapeTemplate(t *template.Template) {
-	for _, tmpl := range t.Templates() {
-		escapeTree(tmpl.Tree)
+// WithDialect sets the SQL dialect used by the template, and all templates
+// associated with it. The dialect's Literal method becomes the sqlliteral
+// function and its placeholder style is used by ExecuteArgs. Templates
+// created with Clone or New inherit the dialect. The return value is the
+// template, so calls can be chained.
+//
+// Templates are escaped using the lexical rules of the dialect in use
+// when they are parsed, so WithDialect panics if any associated templates
+// have been parsed and d has different lexical rules. Such a dialect must
+// be set before the templates are parsed.
+func (t *Template) WithDialect(d Dialect) *Template {
+	t.init()
+	if t.parsed() && syntaxOf(d) != syntaxOf(t.ns.dialect) {
+		panic(fmt.Sprintf("sqltemplate: cannot change dialect from %s to %s after templates have been parsed", t.ns.dialect.Name(), d.Name()))
 	}
+	t.ns.setDialect(d)
+	t.text.Funcs(t.ns.funcs(t.ns.literal))
+	return t
 }
@@ -2,6 +2,7 @@ package sqltemplate
 
 import (
 	"embed"
+	"fmt"
 	"sort"
 	"strings"
 	"testing"
@@ -153,6 +154,70 @@ func TestTemplateDelims(t *testing.T) {
 
 }
 
+func TestTemplateDialect(t *testing.T) {
+	var t0 Template
+	qt.Check(t, t0.Dialect(), qt.Equals, Dialect(Postgres{}))
+	qt.Check(t, New("").Dialect(), qt.Equals, Dialect(Postgres{}))
+
+	t1, err := New("t1").WithDialect(testDialect{}).Parse(`{{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, t1.Dialect(), qt.Equals, Dialect(testDialect{}))
+
+	t2, err := t1.New("t2").Parse(`{{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, t2.Dialect(), qt.Equals, Dialect(testDialect{}))
+
+	t3, err := t1.Clone()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, t3.Dialect(), qt.Equals, Dialect(testDialect{}))
+	t3.WithDialect(Postgres{})
+	qt.Check(t, t1.Dialect(), qt.Equals, Dialect(testDialect{}))
+
+	for _, tmpl := range []*Template{t1, t2} {
+		var b strings.Builder
+		err = tmpl.Execute(&b, "test")
+		qt.Assert(t, err, qt.IsNil)
+		qt.Check(t, b.String(), qt.Equals, "<test>")
+	}
+	var b strings.Builder
+	err = t3.Execute(&b, "test")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, b.String(), qt.Equals, "'test'")
+
+	query, args, err := t1.ExecuteArgs("test")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, "?")
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"test"})
+}
+
+func TestTemplateDialectAfterParse(t *testing.T) {
+	tmpl, err := New("").Parse("SELECT 1 # {{.}}\n")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, func() { tmpl.WithDialect(MySQL{}) }, qt.PanicMatches, `sqltemplate: cannot change dialect from postgres to mysql after templates have been parsed`)
+	qt.Check(t, tmpl.Dialect(), qt.Equals, Dialect(Postgres{}))
+
+	// A dialect with the same lexical rules can still be set.
+	tmpl.WithDialect(testDialect{})
+	qt.Check(t, tmpl.Dialect(), qt.Equals, Dialect(testDialect{}))
+}
+
+// testDialect is a Dialect that makes it obvious when it has been used.
+type testDialect struct {
+	Postgres
+}
+
+func (testDialect) Name() string {
+	return "test"
+}
+
+func (testDialect) Literal(v interface{}) (RawSQL, error) {
+	return RawSQL(fmt.Sprintf("<%v>", v)), nil
+}
+
+func (testDialect) Placeholders() PlaceholderStyle {
+	return QuestionPlaceholders
+}
+
 func TestTemplateExecute(t *testing.T) {
 	var b strings.Builder
 	err := new(Template).Execute(&b, nil)
@@ -165,6 +230,48 @@ func TestTemplateExecute(t *testing.T) {
 	qt.Check(t, b.String(), qt.Equals, "'test'")
 }
 
+func TestTemplateExecuteArgs(t *testing.T) {
+	_, _, err := new(Template).ExecuteArgs(nil)
+	qt.Check(t, err, qt.ErrorMatches, `sqltemplate: "" is an incomplete or empty template`)
+
+	tmpl, err := New("").Parse(`SELECT * FROM {{.Table}} WHERE a = {{.A}} AND b = {{.B}} {{.Order}}`)
+	qt.Assert(t, err, qt.IsNil)
+	query, args, err := tmpl.ExecuteArgs(map[string]interface{}{
+		"Table": Identifier("table"),
+		"A":     "a",
+		"B":     int64(2),
+		"Order": RawSQL("ORDER BY c"),
+	})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `SELECT * FROM "table" WHERE a = $1 AND b = $2 ORDER BY c`)
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"a", int64(2)})
+
+	// Execute still formats literals.
+	var b strings.Builder
+	err = tmpl.Execute(&b, map[string]interface{}{
+		"Table": Identifier("table"),
+		"A":     "a",
+		"B":     int64(2),
+		"Order": RawSQL("ORDER BY c"),
+	})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, b.String(), qt.Equals, `SELECT * FROM "table" WHERE a = 'a' AND b = 2 ORDER BY c`)
+}
+
+func TestTemplateExecuteArgsFuncs(t *testing.T) {
+	tmpl, err := New("").Funcs(FuncMap{
+		"sqlliteral": func(v interface{}) (RawSQL, error) {
+			return RawSQL(fmt.Sprintf("`%v`", v)), nil
+		},
+	}).Parse(`{{.}} {{"x"}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	query, args, err := tmpl.ExecuteArgs(Identifier("id"))
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, "`id` $1")
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"x"})
+}
+
 func TestTemplateExecuteTemplate(t *testing.T) {
 	var b strings.Builder
 	err := new(Template).ExecuteTemplate(&b, "test-template", nil)
@@ -224,6 +331,46 @@ func TestTemplateOption(t *testing.T) {
 	var b strings.Builder
 	err = tmpl.Execute(&b, map[string]string{})
 	qt.Assert(t, err, qt.ErrorMatches, `template: :1:2: executing "" at <\.key>: map has no entry for key "key"`)
+
+	tmpl, err = New("").Option("placeholder=question", "reuseargs=true").Parse(`{{.}} {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	query, args, err := tmpl.ExecuteArgs("test")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, "? ?")
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"test", "test"})
+
+	tmpl, err = New("").Option("reuseargs=true").Parse(`{{.}} {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	query, args, err = tmpl.ExecuteArgs("test")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, "$1 $1")
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"test"})
+
+	qt.Check(t, func() { New("").Option("placeholder=unknown") }, qt.PanicMatches, `unrecognized option: placeholder=unknown`)
+	qt.Check(t, func() { New("").Option("reuseargs=maybe") }, qt.PanicMatches, `unrecognized option: reuseargs=maybe`)
+	qt.Check(t, func() { New("").Option("rawsql=maybe") }, qt.PanicMatches, `unrecognized option: rawsql=maybe`)
+	qt.Check(t, func() { New("").Option("escape=html") }, qt.PanicMatches, `unrecognized option: escape=html`)
+	qt.Check(t, func() { New("").Option("unknown") }, qt.PanicMatches, `unrecognized option: unknown`)
+}
+
+func TestTemplateOptionAfterParse(t *testing.T) {
+	tmpl, err := New("").Parse(`SELECT {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, func() { tmpl.Option("rawsql=deny") }, qt.PanicMatches, `sqltemplate: option rawsql=deny must be set before templates are parsed`)
+
+	qt.Check(t, func() { tmpl.Option("escape=contextual") }, qt.PanicMatches, `sqltemplate: option escape=contextual must be set before templates are parsed`)
+
+	// Setting an option to its current value has no effect.
+	tmpl.Option("rawsql=allow", "escape=literal")
+}
+
+func TestTemplatePlaceholders(t *testing.T) {
+	tmpl, err := New("").Placeholders(AtPPlaceholders).Parse(`{{.}} {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	query, args, err := tmpl.ExecuteArgs("test")
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, "@p1 @p2")
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"test", "test"})
 }
 
 func TestTemplateParse(t *testing.T) {
@@ -0,0 +1,65 @@
+package sqltemplate
+
+import (
+	"strings"
+
+	"github.com/mhilton/sqltemplate/internal/raw"
+)
+
+func init() {
+	raw.TrustedSQL = func(s string) TrustedSQL {
+		return TrustedSQL{s: s}
+	}
+}
+
+// A TrustedSQL value contains part of an SQL query that is known to come
+// from a trusted source. Like RawSQL it is inserted into the template
+// output verbatim, but unlike RawSQL a TrustedSQL value cannot be created
+// from an arbitrary string. TrustedSQL values can only be created from
+// string constants in the program source, by combining other TrustedSQL
+// values, or by using the unsafesql package, which should be audited
+// carefully.
+//
+// The zero TrustedSQL value is empty.
+type TrustedSQL struct {
+	s string
+}
+
+// A stringConstant is a string that can only be created from an untyped
+// string constant by callers outside this package.
+type stringConstant string
+
+// TrustedSQLFromConstant returns a TrustedSQL value containing the SQL
+// text s. The argument must be an untyped string constant, for example:
+//
+//	sqltemplate.TrustedSQLFromConstant("ORDER BY created DESC")
+func TrustedSQLFromConstant(s stringConstant) TrustedSQL {
+	return TrustedSQL{s: string(s)}
+}
+
+// TrustedSQLConcat returns a TrustedSQL value containing the
+// concatenation of the SQL text of all the given values.
+func TrustedSQLConcat(ts ...TrustedSQL) TrustedSQL {
+	return TrustedSQLJoin(ts, TrustedSQL{})
+}
+
+// TrustedSQLJoin returns a TrustedSQL value containing the SQL text of
+// all the values in ts separated by the SQL text of sep.
+func TrustedSQLJoin(ts []TrustedSQL, sep TrustedSQL) TrustedSQL {
+	ss := make([]string, len(ts))
+	for i, t := range ts {
+		ss[i] = t.s
+	}
+	return TrustedSQL{s: strings.Join(ss, sep.s)}
+}
+
+// SQLLiteral implements SQLLiteraler by returning the SQL text of t
+// unchanged, for every dialect.
+func (t TrustedSQL) SQLLiteral(Dialect) (RawSQL, error) {
+	return RawSQL(t.s), nil
+}
+
+// String returns the SQL text of t.
+func (t TrustedSQL) String() string {
+	return t.s
+}
@@ -0,0 +1,78 @@
+package sqltemplate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestTrustedSQL(t *testing.T) {
+	qt.Check(t, TrustedSQL{}.String(), qt.Equals, "")
+	qt.Check(t, TrustedSQLFromConstant("ORDER BY a").String(), qt.Equals, "ORDER BY a")
+	qt.Check(t, TrustedSQLConcat().String(), qt.Equals, "")
+	qt.Check(t, TrustedSQLConcat(
+		TrustedSQLFromConstant("a"),
+		TrustedSQLFromConstant(", "),
+		TrustedSQLFromConstant("b"),
+	).String(), qt.Equals, "a, b")
+	qt.Check(t, TrustedSQLJoin([]TrustedSQL{
+		TrustedSQLFromConstant("a = 1"),
+		TrustedSQLFromConstant("b = 2"),
+	}, TrustedSQLFromConstant(" AND ")).String(), qt.Equals, "a = 1 AND b = 2")
+}
+
+func TestTrustedSQLDialects(t *testing.T) {
+	v := TrustedSQLFromConstant("now()")
+	for _, d := range []Dialect{BigQuery{}, ClickHouse{}, DuckDB{}, MySQL{}, Oracle{}, Postgres{}, SQLite{}, SQLServer{}} {
+		t.Run(fmt.Sprintf("%T", d), func(t *testing.T) {
+			s, err := d.Literal(v)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, RawSQL("now()"))
+
+			s, err = d.Literal(&v)
+			qt.Assert(t, err, qt.IsNil)
+			qt.Check(t, s, qt.Equals, RawSQL("now()"))
+		})
+	}
+}
+
+func TestTrustedSQLIgnoresRegistry(t *testing.T) {
+	var r Registry
+	r.RegisterEncoder(reflect.TypeOf((*fmt.Stringer)(nil)).Elem(), func(v interface{}) (RawSQL, error) {
+		return "<stringer>", nil
+	})
+	s, err := Postgres{Registry: &r}.Literal(TrustedSQLFromConstant("a"))
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, s, qt.Equals, RawSQL("a"))
+}
+
+func TestTrustedSQLInTemplate(t *testing.T) {
+	tmpl, err := New("").Option("rawsql=deny").Funcs(FuncMap{
+		"order": func(desc bool) TrustedSQL {
+			if desc {
+				return TrustedSQLFromConstant("DESC")
+			}
+			return TrustedSQLFromConstant("ASC")
+		},
+	}).Parse(`SELECT * FROM t WHERE a = {{.A}} ORDER BY b {{order .Desc}}`)
+	qt.Assert(t, err, qt.IsNil)
+
+	data := map[string]interface{}{"A": "x", "Desc": true}
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, data)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT * FROM t WHERE a = 'x' ORDER BY b DESC`)
+
+	query, args, err := tmpl.ExecuteArgs(data)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, query, qt.Equals, `SELECT * FROM t WHERE a = $1 ORDER BY b DESC`)
+	qt.Check(t, args, qt.DeepEquals, []interface{}{"x"})
+
+	sb.Reset()
+	err = tmpl.Execute(&sb, map[string]interface{}{"A": TrustedSQLFromConstant("y"), "Desc": false})
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, `SELECT * FROM t WHERE a = y ORDER BY b ASC`)
+}
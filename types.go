@@ -1,9 +1,595 @@
 package sqltemplate
 
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // An Identifier holds a value that should be formatted as an identifier in
 // the SQL output.
 type Identifier string
 
 // A RawSQL value contains part of an SQL query that will be inserted into
 // the template output verbatim.
+//
+// Any string can be converted to a RawSQL value, so care must be taken
+// never to build one from untrusted input. New code should prefer
+// TrustedSQL, which can only be created from trusted sources. Where a
+// RawSQL value must be built from a non-constant string, using
+// unsafesql.RawSQL makes the conversion easy to find when auditing code.
 type RawSQL string
+
+// An Array holds a slice or array value that should be formatted as an
+// SQL array with elements of the given type.
+//
+// Array implements database/sql/driver.Valuer, the value is a string in
+// the PostgreSQL array input format.
+type Array struct {
+	// Elems is the slice or array containing the elements.
+	Elems interface{}
+
+	// ElemType is the SQL type of the elements, for example "text". It
+	// must be a valid type name, a value with any other ElemType cannot
+	// be formatted.
+	ElemType string
+}
+
+// Value implements driver.Valuer. If Elems is a nil slice then the value
+// is nil.
+func (a Array) Value() (driver.Value, error) {
+	s, ok, err := a.text(0)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return s, nil
+}
+
+// text formats a in the PostgreSQL array input format, depth is the
+// number of values that contain a. If Elems is a nil slice then ok is
+// false.
+func (a Array) text(depth int) (s string, ok bool, err error) {
+	rv, err := a.elems()
+	if err != nil {
+		return "", false, err
+	}
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return "", false, nil
+	}
+	var sb strings.Builder
+	if err := writeArrayText(&sb, rv, depth); err != nil {
+		return "", false, err
+	}
+	return sb.String(), true, nil
+}
+
+// elems returns the slice or array held in Elems.
+func (a Array) elems() (reflect.Value, error) {
+	rv := reflect.ValueOf(a.Elems)
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("cannot use %T as Array elements", a.Elems)
+	}
+	return rv, nil
+}
+
+// writeArrayText writes the slice or array held in rv to sb in the
+// PostgreSQL array input format. Elements that are themselves slices or
+// arrays are written as nested arrays, depth is the number of values
+// that contain rv. Other elements are converted using postgresText.
+func writeArrayText(sb *strings.Builder, rv reflect.Value, depth int) error {
+	if depth >= postgresMaxDepth {
+		return fmt.Errorf("cannot format %s nested more than %d levels deep, the value might contain a cycle", rv.Type(), postgresMaxDepth)
+	}
+	sb.WriteString("{")
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		e := rv.Index(i)
+		for (e.Kind() == reflect.Interface || e.Kind() == reflect.Ptr) && !e.IsNil() && !e.Type().Implements(valuerType) {
+			e = e.Elem()
+		}
+		if isNestedArray(e.Type()) {
+			if err := writeArrayText(sb, e, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		s, ok, err := postgresText(elemInterface(e), depth+1)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			sb.WriteString("NULL")
+			continue
+		}
+		writeQuotedText(sb, s)
+	}
+	sb.WriteString("}")
+	return nil
+}
+
+// isNestedArray reports whether values of type t, when they are the
+// elements of an array, are written as nested arrays.
+func isNestedArray(t reflect.Type) bool {
+	if t.Implements(valuerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return !isUUIDType(t)
+	}
+	return false
+}
+
+// formatUUID formats u in the standard 8-4-4-4-12 hexadecimal form.
+func formatUUID(u [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// A JSON holds a value that should be formatted as a JSON document. The
+// document is produced by encoding Data with encoding/json.
+//
+// JSON implements database/sql/driver.Valuer, so in dialects that have
+// no specific support for JSON documents the value is formatted as a
+// string containing the encoded document.
+type JSON struct {
+	Data interface{}
+}
+
+// Value implements driver.Valuer by returning the encoded document as a
+// string.
+func (j JSON) Value() (driver.Value, error) {
+	buf, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// An Interval holds a period of time in the same form as a PostgreSQL
+// interval. The months, days and microseconds are held separately as
+// their lengths relative to each other depend on the point in time they
+// are applied to.
+//
+// Interval implements database/sql/driver.Valuer, the value is a string
+// in the PostgreSQL interval input format.
+type Interval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+// Value implements driver.Valuer.
+func (i Interval) Value() (driver.Value, error) {
+	return formatInterval(int64(i.Months), int64(i.Days), i.Microseconds, 0), nil
+}
+
+// A Date holds a time stamp of which only the date is used. The date is
+// taken from the time stamp in its own location.
+//
+// Date implements database/sql/driver.Valuer, the value is the time
+// stamp.
+type Date time.Time
+
+// Value implements driver.Valuer.
+func (t Date) Value() (driver.Value, error) {
+	return time.Time(t), nil
+}
+
+// A TimeOfDay holds a time stamp of which only the time of day is used.
+// The time of day is taken from the time stamp in its own location.
+//
+// TimeOfDay implements database/sql/driver.Valuer, the value is the time
+// stamp.
+type TimeOfDay time.Time
+
+// Value implements driver.Valuer.
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return time.Time(t), nil
+}
+
+// A Timestamp holds a time stamp that is used without a time zone. The
+// date and time of day are taken from the time stamp in its own
+// location.
+//
+// Timestamp implements database/sql/driver.Valuer, the value is the time
+// stamp.
+type Timestamp time.Time
+
+// Value implements driver.Valuer.
+func (t Timestamp) Value() (driver.Value, error) {
+	return time.Time(t), nil
+}
+
+// A TimestampTZ holds a time stamp that is used with a time zone.
+//
+// TimestampTZ implements database/sql/driver.Valuer, the value is the
+// time stamp.
+type TimestampTZ time.Time
+
+// Value implements driver.Valuer.
+func (t TimestampTZ) Value() (driver.Value, error) {
+	return time.Time(t), nil
+}
+
+// A Range holds a range of values of type T. The zero value holds the
+// range of values from the zero value of T, exclusive, to the zero value
+// of T, exclusive.
+//
+// Range implements database/sql/driver.Valuer, the value is a string in
+// the PostgreSQL range input format.
+type Range[T any] struct {
+	// Lower and Upper are the bounds of the range.
+	Lower, Upper T
+
+	// LowerInclusive and UpperInclusive determine whether the
+	// respective bound is included in the range.
+	LowerInclusive, UpperInclusive bool
+
+	// LowerUnbounded and UpperUnbounded are set if the range has no
+	// lower or upper bound respectively, in which case the
+	// corresponding bound value is ignored.
+	LowerUnbounded, UpperUnbounded bool
+
+	// Empty is set if the range contains no values, in which case all
+	// the other fields are ignored.
+	Empty bool
+
+	// Type is the SQL range type, for example "tstzrange". If this is
+	// empty then the type is determined from T where possible. It must
+	// be a valid type name.
+	Type string
+}
+
+// Value implements driver.Valuer.
+func (r Range[T]) Value() (driver.Value, error) {
+	var sb strings.Builder
+	if err := r.writeText(&sb); err != nil {
+		return nil, err
+	}
+	return sb.String(), nil
+}
+
+// writeText writes r to sb in the PostgreSQL range input format.
+func (r Range[T]) writeText(sb *strings.Builder) error {
+	if r.Empty {
+		sb.WriteString("empty")
+		return nil
+	}
+	sb.WriteString(r.bounds()[:1])
+	if !r.LowerUnbounded {
+		if err := writeRangeBound(sb, r.Lower); err != nil {
+			return err
+		}
+	}
+	sb.WriteString(",")
+	if !r.UpperUnbounded {
+		if err := writeRangeBound(sb, r.Upper); err != nil {
+			return err
+		}
+	}
+	sb.WriteString(r.bounds()[1:])
+	return nil
+}
+
+// bounds returns the bound characters of r as used by the range
+// constructor functions, for example "[)".
+func (r Range[T]) bounds() string {
+	b := []byte("()")
+	if r.LowerInclusive && !r.LowerUnbounded {
+		b[0] = '['
+	}
+	if r.UpperInclusive && !r.UpperUnbounded {
+		b[1] = ']'
+	}
+	return string(b)
+}
+
+// writeRangeBound writes the bound v to sb as a double quoted value in
+// the PostgreSQL range input format.
+func writeRangeBound(sb *strings.Builder, v interface{}) error {
+	s, ok, err := postgresText(v, 0)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("cannot use NULL as a range bound")
+	}
+	writeQuotedText(sb, s)
+	return nil
+}
+
+// postgresText converts v to the text form used in the PostgreSQL array,
+// range and composite input formats. Values are first converted in the
+// same way as query arguments, see Postgres.arg, so that they have the
+// same text form as in ExecuteArgs. Depth is the number of values that
+// contain v. If v is NULL then ok is false.
+func postgresText(v interface{}, depth int) (s string, ok bool, err error) {
+	if depth >= postgresMaxDepth {
+		return "", false, fmt.Errorf("cannot format %T nested more than %d levels deep, the value might contain a cycle", v, postgresMaxDepth)
+	}
+	v, err = Postgres{}.arg(v)
+	if err != nil {
+		return "", false, err
+	}
+	switch v1 := v.(type) {
+	case RawSQL:
+		// The value can only be formatted as an expression.
+		return "", false, fmt.Errorf("cannot represent %s as text", v1)
+	case Array:
+		return v1.text(depth)
+	case Row:
+		return v1.text(depth)
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 && !rv.Type().Implements(valuerType) {
+		if rv.IsNil() {
+			return "", false, nil
+		}
+		return `\x` + hex.EncodeToString(rv.Bytes()), true, nil
+	}
+	return textValue(v)
+}
+
+// textValue converts v to text as database/sql converts query arguments.
+// If v is NULL then ok is false.
+func textValue(v interface{}) (s string, ok bool, err error) {
+	v, err = driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return "", false, err
+	}
+	switch v := v.(type) {
+	case nil:
+		return "", false, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true, nil
+	case []byte:
+		return string(v), true, nil
+	default:
+		return fmt.Sprint(v), true, nil
+	}
+}
+
+// writeQuotedText writes s to sb as a double quoted value in the
+// PostgreSQL range and composite input formats.
+func writeQuotedText(sb *strings.Builder, s string) {
+	sb.WriteString(`"`)
+	sb.WriteString(quotedTextEscaper.Replace(s))
+	sb.WriteString(`"`)
+}
+
+var quotedTextEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+
+// A Multirange holds a set of ranges of values of type T.
+//
+// Multirange implements database/sql/driver.Valuer, the value is a
+// string in the PostgreSQL multirange input format.
+type Multirange[T any] struct {
+	// Ranges contains the ranges in the multirange.
+	Ranges []Range[T]
+
+	// Type is the SQL multirange type, for example "tstzmultirange". If
+	// this is empty then the type is determined from T where possible.
+	// It must be a valid type name.
+	Type string
+}
+
+// Value implements driver.Valuer.
+func (m Multirange[T]) Value() (driver.Value, error) {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, r := range m.Ranges {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		if err := r.writeText(&sb); err != nil {
+			return nil, err
+		}
+	}
+	sb.WriteString("}")
+	return sb.String(), nil
+}
+
+// A Row holds a struct value that should be formatted as a composite
+// value. Only exported fields are included, a field can be excluded with
+// the struct tag `sql:"-"`, and a field can be given a name with a struct
+// tag such as `sql:"name"`, otherwise the name is the name of the field.
+//
+// Row implements database/sql/driver.Valuer, the value is a string in
+// the PostgreSQL composite input format.
+type Row struct {
+	// Struct is the struct, or a pointer to the struct, containing the
+	// fields.
+	Struct interface{}
+
+	// Type is the SQL composite type of the value, for example
+	// "inventory_item". If this is empty then the value is not cast.
+	// It must be a valid type name.
+	Type string
+
+	// Fields contains the names of the fields to include, in the order
+	// they appear in the composite type. If this is empty then all
+	// fields are included in the order they are declared.
+	Fields []string
+}
+
+// Value implements driver.Valuer.
+func (r Row) Value() (driver.Value, error) {
+	s, ok, err := r.text(0)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return s, nil
+}
+
+// text formats r in the PostgreSQL composite input format, depth is the
+// number of values that contain r. If Struct is a nil pointer then ok is
+// false.
+func (r Row) text(depth int) (s string, ok bool, err error) {
+	fields, err := r.fields()
+	if err != nil || fields == nil {
+		return "", false, err
+	}
+	var sb strings.Builder
+	sb.WriteString("(")
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		s, ok, err := postgresText(elemInterface(f), depth+1)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			writeQuotedText(&sb, s)
+		}
+	}
+	sb.WriteString(")")
+	return sb.String(), true, nil
+}
+
+// fields returns the values of the fields included in the row. If
+// Struct is a nil pointer then fields returns nil.
+func (r Row) fields() ([]reflect.Value, error) {
+	rv := reflect.ValueOf(r.Struct)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot use %T as Row value", r.Struct)
+	}
+	fields := structFields(rv)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%T has no fields that can be formatted", r.Struct)
+	}
+	if len(r.Fields) == 0 {
+		values := make([]reflect.Value, len(fields))
+		for i, f := range fields {
+			values[i] = f.value
+		}
+		return values, nil
+	}
+	values := make([]reflect.Value, 0, len(r.Fields))
+	for _, name := range r.Fields {
+		i := 0
+		for ; i < len(fields); i++ {
+			if fields[i].name == name {
+				break
+			}
+		}
+		if i == len(fields) {
+			return nil, fmt.Errorf("%T has no field %q", r.Struct, name)
+		}
+		values = append(values, fields[i].value)
+	}
+	return values, nil
+}
+
+// A structField is a field of a struct included in a composite value.
+type structField struct {
+	name  string
+	value reflect.Value
+}
+
+// structFields returns the fields of the struct held in rv that are
+// included in a composite value, in the order they are declared.
+func structFields(rv reflect.Value) []structField {
+	var fields []structField
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("sql"); ok {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		fields = append(fields, structField{name: name, value: rv.Field(i)})
+	}
+	return fields
+}
+
+// hasSQLTags reports whether the struct type t has an exported field
+// with an sql struct tag.
+func hasSQLTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if _, ok := f.Tag.Lookup("sql"); ok && f.PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// formatInterval formats an interval in the PostgreSQL interval input
+// format, for example "1 year 2 months 3 days 4 hours 5.5 seconds". The
+// time part of the interval is us microseconds plus ns nanoseconds, ns
+// must have the same sign as us. Each unit carries its own sign so mixed
+// sign intervals are represented exactly.
+func formatInterval(months, days, us, ns int64) string {
+	var parts []string
+	add := func(n int64, unit string) {
+		if n == 0 {
+			return
+		}
+		if n != 1 && n != -1 {
+			unit += "s"
+		}
+		parts = append(parts, strconv.FormatInt(n, 10)+" "+unit)
+	}
+	add(months/12, "year")
+	add(months%12, "month")
+	add(days, "day")
+	const (
+		second = 1000000
+		minute = 60 * second
+		hour   = 60 * minute
+	)
+	add(us/hour, "hour")
+	us %= hour
+	add(us/minute, "minute")
+	us %= minute
+	if us != 0 || ns != 0 {
+		parts = append(parts, formatSeconds(us, ns))
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatSeconds formats a period of less than a minute, given as us
+// microseconds plus ns nanoseconds, as a decimal number of seconds.
+func formatSeconds(us, ns int64) string {
+	var sign string
+	if us < 0 || ns < 0 {
+		sign = "-"
+		us, ns = -us, -ns
+	}
+	s := strconv.FormatInt(us/1000000, 10)
+	if frac := (us%1000000)*1000 + ns; frac != 0 {
+		f := strconv.FormatInt(frac+1000000000, 10)[1:]
+		s += "." + strings.TrimRight(f, "0")
+	}
+	unit := " seconds"
+	if sign == "" && s == "1" {
+		unit = " second"
+	}
+	return sign + s + unit
+}
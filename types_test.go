@@ -0,0 +1,101 @@
+package sqltemplate
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestArrayValue(t *testing.T) {
+	v, err := Array{Elems: []int{1, 2}, ElemType: "integer"}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`{"1","2"}`))
+
+	s := "a\"b"
+	v, err = Array{Elems: []interface{}{&s, nil, []byte("c"), [][]int{{1}, {2}}}}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`{"a\"b",NULL,"\\x63",{{"1"},{"2"}}}`))
+
+	v, err = Array{Elems: []testUUID{{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}}}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`{"123e4567-e89b-12d3-a456-426614174000"}`))
+
+	v, err = Array{Elems: []string(nil)}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.IsNil)
+
+	cycle := []interface{}{nil}
+	cycle[0] = cycle
+	_, err = Array{Elems: cycle}.Value()
+	qt.Check(t, err, qt.ErrorMatches, `.*the value might contain a cycle`)
+
+	_, err = Array{Elems: "abc"}.Value()
+	qt.Check(t, err, qt.ErrorMatches, `cannot use string as Array elements`)
+}
+
+func TestJSONValue(t *testing.T) {
+	v, err := JSON{Data: map[string]int{"a": 1}}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, `{"a":1}`)
+
+	_, err = JSON{Data: make(chan bool)}.Value()
+	qt.Check(t, err, qt.ErrorMatches, `json: unsupported type: chan bool`)
+}
+
+func TestIntervalValue(t *testing.T) {
+	v, err := Interval{Months: 3, Days: 1, Microseconds: 60000000}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value("3 months 1 day 1 minute"))
+}
+
+func TestTimeValues(t *testing.T) {
+	tm := time.Date(2020, time.February, 2, 12, 30, 45, 0, time.UTC)
+	for _, v := range []driver.Valuer{Date(tm), TimeOfDay(tm), Timestamp(tm), TimestampTZ(tm)} {
+		v1, err := v.Value()
+		qt.Assert(t, err, qt.IsNil)
+		qt.Check(t, v1, qt.Equals, driver.Value(tm))
+	}
+}
+
+func TestRangeValue(t *testing.T) {
+	v, err := Range[int]{Lower: 1, Upper: 10, LowerInclusive: true}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`["1","10")`))
+
+	v, err = Range[string]{Lower: `a"b\c`, UpperUnbounded: true, UpperInclusive: true}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`("a\"b\\c",)`))
+
+	v, err = Range[time.Time]{Empty: true}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`empty`))
+
+	_, err = Range[*int]{}.Value()
+	qt.Check(t, err, qt.ErrorMatches, `cannot use NULL as a range bound`)
+}
+
+func TestMultirangeValue(t *testing.T) {
+	v, err := Multirange[int]{Ranges: []Range[int]{
+		{Lower: 1, Upper: 3, LowerInclusive: true},
+		{Empty: true},
+	}}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`{["1","3"),empty}`))
+}
+
+func TestRowValue(t *testing.T) {
+	v, err := Row{Struct: struct {
+		A string
+		B *int
+		C float64 `sql:"-"`
+		D time.Time
+	}{A: `a"b`, C: 1, D: time.Date(2020, time.February, 2, 12, 0, 0, 0, time.UTC)}}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.Equals, driver.Value(`("a\"b",,"2020-02-02T12:00:00Z")`))
+
+	v, err = Row{Struct: (*struct{ A int })(nil)}.Value()
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, v, qt.IsNil)
+}
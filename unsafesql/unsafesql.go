@@ -0,0 +1,31 @@
+// Package unsafesql provides conversions that create sqltemplate values
+// that are inserted into SQL queries verbatim from arbitrary strings.
+//
+// The functions in this package bypass the guarantees provided by
+// sqltemplate.TrustedSQL and can cause SQL injection vulnerabilities if
+// they are given untrusted input. Every use of this package should be
+// audited carefully.
+package unsafesql
+
+import (
+	"github.com/mhilton/sqltemplate"
+	"github.com/mhilton/sqltemplate/internal/raw"
+)
+
+var trustedSQL = raw.TrustedSQL.(func(string) sqltemplate.TrustedSQL)
+
+// TrustedSQLFromString returns a TrustedSQL value containing the SQL text
+// s. The caller must ensure that s does not contain untrusted input.
+func TrustedSQLFromString(s string) sqltemplate.TrustedSQL {
+	return trustedSQL(s)
+}
+
+// RawSQL returns a RawSQL value containing the SQL text s. The caller
+// must ensure that s does not contain untrusted input.
+//
+// This is equivalent to the conversion sqltemplate.RawSQL(s), but makes
+// the conversion easy to find when auditing code. New code should prefer
+// TrustedSQL values.
+func RawSQL(s string) sqltemplate.RawSQL {
+	return sqltemplate.RawSQL(s)
+}
@@ -0,0 +1,33 @@
+package unsafesql_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/mhilton/sqltemplate"
+	"github.com/mhilton/sqltemplate/unsafesql"
+)
+
+func TestTrustedSQLFromString(t *testing.T) {
+	column := "created"
+	v := unsafesql.TrustedSQLFromString("ORDER BY " + column)
+	qt.Check(t, v.String(), qt.Equals, "ORDER BY created")
+
+	s, err := sqltemplate.PostgresLiteral(v)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, s, qt.Equals, sqltemplate.RawSQL("ORDER BY created"))
+
+	tmpl, err := sqltemplate.New("").Option("rawsql=deny").Parse(`SELECT * FROM t {{.}}`)
+	qt.Assert(t, err, qt.IsNil)
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, v)
+	qt.Assert(t, err, qt.IsNil)
+	qt.Check(t, sb.String(), qt.Equals, "SELECT * FROM t ORDER BY created")
+}
+
+func TestRawSQL(t *testing.T) {
+	column := "created"
+	qt.Check(t, unsafesql.RawSQL("ORDER BY "+column), qt.Equals, sqltemplate.RawSQL("ORDER BY created"))
+}